@@ -0,0 +1,518 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/Konstantin8105/ms/fem"
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// results of analysis for present geometry of model
+type results struct {
+	static   *staticResult
+	modal    *fem.Modal
+	buckling *fem.Buckling
+}
+
+type staticResult struct {
+	fem.Static
+	beams  []uint // element id of each beam
+	plates []uint // element id of each plate
+}
+
+// femModel return model for finite element analysis by groups of model.
+// Element ids of beams and plates is in same order as in model.
+func (mm *Model) femModel() (fm fem.Model, beams, plates []uint, err error) {
+	fm.Nodes = make([][3]float64, len(mm.Coords))
+	for i := range mm.Coords {
+		fm.Nodes[i] = mm.Coords[i].Point3d
+	}
+	isLine := func(id uint) bool {
+		return int(id) < len(mm.Elements) && mm.Elements[id].ElementType == Line2
+	}
+	isPlate := func(id uint) bool {
+		if len(mm.Elements) <= int(id) {
+			return false
+		}
+		t := mm.Elements[id].ElementType
+		return t == Triangle3 || t == Quadr4
+	}
+	isNode := func(id uint) bool {
+		return int(id) < len(mm.Coords) && !mm.Coords[id].Removed
+	}
+	var (
+		materials = map[uint]*groups.Material{}
+		sections  = map[uint]*groups.Section{}
+		thickness = map[uint]*groups.Thickness{}
+		releases  = map[uint]*groups.LineReleases{}
+		loads     = map[uint][3]float64{} // per length or per area
+	)
+	walkGroups(mm.GetRootGroup(), func(gr groups.Group) {
+		switch g := gr.(type) {
+		case *groups.Material:
+			for _, id := range g.Elements {
+				materials[id] = g
+			}
+		case *groups.Section:
+			for _, id := range g.Elements {
+				sections[id] = g
+			}
+		case *groups.Thickness:
+			for _, id := range g.Elements {
+				thickness[id] = g
+			}
+		case *groups.LineReleases:
+			for _, id := range g.Elements {
+				releases[id] = g
+			}
+		case *groups.LineLoads:
+			for _, id := range g.Elements {
+				if !isLine(id) {
+					continue
+				}
+				q := loads[id]
+				for i := range q {
+					q[i] += g.Load[i]
+				}
+				loads[id] = q
+			}
+		case *groups.PlateLoads:
+			for _, id := range g.Elements {
+				if !isPlate(id) {
+					continue
+				}
+				q := loads[id]
+				for i := range q {
+					q[i] += g.Load[i]
+				}
+				loads[id] = q
+			}
+		case *groups.NodeSupports:
+			for _, id := range g.Nodes {
+				if !isNode(id) {
+					continue
+				}
+				s := fem.Support{Node: int(id)}
+				for d, sup := range g.Direction {
+					switch sup.Kind {
+					case groups.Fixed:
+						s.Fixed[d] = true
+					case groups.Displacement:
+						s.Fixed[d] = true
+						s.Displacement[d] = sup.Value
+					case groups.Spring:
+						s.Stiffness[d] = sup.Value
+					}
+				}
+				fm.Supports = append(fm.Supports, s)
+			}
+		case *groups.RigidLinks:
+			if !isNode(g.Master) {
+				return
+			}
+			l := fem.Link{Master: int(g.Master), Direction: g.Direction}
+			for _, id := range g.Nodes {
+				if isNode(id) {
+					l.Slaves = append(l.Slaves, int(id))
+				}
+			}
+			fm.Links = append(fm.Links, l)
+		case *groups.NodeMasses:
+			for _, id := range g.Nodes {
+				if isNode(id) {
+					fm.Masses = append(fm.Masses, fem.Mass{Node: int(id), Mass: g.Mass})
+				}
+			}
+		case *groups.NodeLoads:
+			for _, id := range g.Nodes {
+				if isNode(id) {
+					fm.Loads = append(fm.Loads, fem.Load{Node: int(id), Forces: g.Forces})
+				}
+			}
+		}
+	})
+	for id := range mm.Elements {
+		if !isLine(uint(id)) {
+			continue
+		}
+		el := mm.Elements[id]
+		mat, ok := materials[uint(id)]
+		if !ok {
+			err = fmt.Errorf("line %d without material", id)
+			return
+		}
+		sec, ok := sections[uint(id)]
+		if !ok {
+			err = fmt.Errorf("line %d without section", id)
+			return
+		}
+		b := fem.Beam{
+			Nodes:   [2]int{el.Indexes[0], el.Indexes[1]},
+			E:       mat.E,
+			G:       mat.E / (2 * (1 + mat.Nu)),
+			A:       sec.A,
+			Iy:      sec.Iy,
+			Iz:      sec.Iz,
+			J:       sec.J,
+			Load:    loads[uint(id)],
+			Density: mat.Density,
+		}
+		if r, ok := releases[uint(id)]; ok {
+			b.Release = [2][6]bool{r.Begin, r.End}
+		}
+		fm.Beams = append(fm.Beams, b)
+		beams = append(beams, uint(id))
+	}
+	for id := range mm.Elements {
+		if !isPlate(uint(id)) {
+			continue
+		}
+		el := mm.Elements[id]
+		mat, ok := materials[uint(id)]
+		if !ok {
+			err = fmt.Errorf("plate %d without material", id)
+			return
+		}
+		th, ok := thickness[uint(id)]
+		if !ok {
+			err = fmt.Errorf("plate %d without thickness", id)
+			return
+		}
+		fm.Plates = append(fm.Plates, fem.Plate{
+			Nodes:     append([]int{}, el.Indexes...),
+			E:         mat.E,
+			Nu:        mat.Nu,
+			Thickness: th.Thickness,
+			Density:   mat.Density,
+			Load:      loads[uint(id)],
+		})
+		plates = append(plates, uint(id))
+	}
+	if len(fm.Beams) == 0 && len(fm.Plates) == 0 {
+		err = fmt.Errorf("model without lines and plates")
+	}
+	return
+}
+
+func (mm *Model) LinearStatic() (report string, err error) {
+	logger.Printf("LinearStatic")
+	mm.results.static = nil
+	fm, beams, plates, err := mm.femModel()
+	if err != nil {
+		logger.Printf("LinearStatic: %v", err)
+		return
+	}
+	st, err := fm.LinearStatic()
+	if err != nil {
+		logger.Printf("LinearStatic: %v", err)
+		return
+	}
+	mm.results.static = &staticResult{Static: st, beams: beams, plates: plates}
+	// report
+	var (
+		node  int
+		dmax  float64
+		total [6]float64
+	)
+	for i, d := range st.Displacements {
+		if v := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2]); dmax < v {
+			node, dmax = i, v
+		}
+	}
+	for i := range st.Reactions {
+		for d := range total {
+			total[d] += st.Reactions[i][d]
+		}
+	}
+	report = fmt.Sprintf("Maximal displacement %.5g at node %d\n", dmax, node)
+	if 0 < len(plates) {
+		var smax float64
+		for i, s := range st.Stress {
+			if smax < s {
+				node, smax = i, s
+			}
+		}
+		report += fmt.Sprintf("Maximal stress in plates %.5g at node %d\n", smax, node)
+	}
+	report += "Sum of reactions:\n"
+	for d, name := range []string{"Fx", "Fy", "Fz", "Mx", "My", "Mz"} {
+		report += fmt.Sprintf("%s = %.5g\n", name, total[d])
+	}
+	return
+}
+
+// GetDisplacements return displacements of nodes from result of analysis
+func (mm *Model) GetDisplacements() (ds [][6]float64) {
+	if mm.results.static == nil {
+		return nil
+	}
+	return mm.results.static.Displacements
+}
+
+// StaticTable return table of displacements, reactions and forces
+// at ends of beams with comma separated values
+func (mm *Model) StaticTable() (table string, err error) {
+	st := mm.results.static
+	if st == nil {
+		err = fmt.Errorf("no results of linear static analysis")
+		return
+	}
+	var sb strings.Builder
+	row := func(name string, id uint, vs []float64) {
+		fmt.Fprintf(&sb, "%s,%d", name, id)
+		for _, v := range vs {
+			fmt.Fprintf(&sb, ",%.6e", v)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+	fmt.Fprintf(&sb, "Displacements in global coordinates\n")
+	fmt.Fprintf(&sb, "Type,Node,Dx,Dy,Dz,Rx,Ry,Rz\n")
+	for i, d := range st.Displacements {
+		if mm.Coords[i].Removed {
+			continue
+		}
+		row("Displacement", uint(i), d[:])
+	}
+	fmt.Fprintf(&sb, "\nReactions in global coordinates\n")
+	fmt.Fprintf(&sb, "Type,Node,Fx,Fy,Fz,Mx,My,Mz\n")
+	for i, r := range st.Reactions {
+		if r == [6]float64{} {
+			continue
+		}
+		row("Reaction", uint(i), r[:])
+	}
+	fmt.Fprintf(&sb, "\nForces at ends of lines in local coordinates\n")
+	fmt.Fprintf(&sb, "Type,Element,Node,N,Qy,Qz,T,My,Mz\n")
+	for i, f := range st.BeamForces {
+		id := st.beams[i]
+		for end := 0; end < 2; end++ {
+			row(fmt.Sprintf("Force,%d", id), uint(mm.Elements[id].Indexes[end]), f[6*end:6*end+6])
+		}
+	}
+	fmt.Fprintf(&sb, "\nForces in nodes of plates in local coordinates\n")
+	fmt.Fprintf(&sb, "Type,Element,Node,Nx,Ny,Nxy,Mx,My,Mxy,Qx,Qy\n")
+	for i, fs := range st.PlateForces {
+		id := st.plates[i]
+		for k, f := range fs {
+			row(fmt.Sprintf("Plate,%d", id), uint(mm.Elements[id].Indexes[k]), f[:])
+		}
+	}
+	fmt.Fprintf(&sb, "\nAverage von Mises stress in nodes of plates\n")
+	fmt.Fprintf(&sb, "Type,Node,Stress\n")
+	if 0 < len(st.plates) {
+		for i, s := range st.Stress {
+			if s == 0 {
+				continue
+			}
+			row("Stress", uint(i), []float64{s})
+		}
+	}
+	table = sb.String()
+	return
+}
+
+func (mm *Model) ExportStaticTable(filename string) (err error) {
+	logger.Printf("ExportStaticTable")
+	table, err := mm.StaticTable()
+	if err != nil {
+		return
+	}
+	return os.WriteFile(filename, []byte(table), 0666)
+}
+
+// Modal run modal analysis for lowest modes with consistent or
+// lumped mass
+func (mm *Model) Modal(modes uint, lumped bool) (report string, err error) {
+	logger.Printf("Modal")
+	mm.results.modal = nil
+	fm, _, _, err := mm.femModel()
+	if err != nil {
+		logger.Printf("Modal: %v", err)
+		return
+	}
+	md, err := fm.Modal(int(modes), lumped)
+	if err != nil {
+		logger.Printf("Modal: %v", err)
+		return
+	}
+	mm.results.modal = &md
+	report = fmt.Sprintf("Total mass: X=%.5g Y=%.5g Z=%.5g\n",
+		md.TotalMass[0], md.TotalMass[1], md.TotalMass[2])
+	report += "Mode, Frequency(Hz), Participation X, Y, Z, Effective mass X(%), Y(%), Z(%)\n"
+	var sum [3]float64
+	for i, f := range md.Frequencies {
+		p, e := md.Participation[i], md.EffectiveMass[i]
+		report += fmt.Sprintf("%d, %.5g, %.4g, %.4g, %.4g", i+1, f, p[0], p[1], p[2])
+		for d := range e {
+			var ratio float64
+			if 0 < md.TotalMass[d] {
+				ratio = 100 * e[d] / md.TotalMass[d]
+			}
+			sum[d] += ratio
+			report += fmt.Sprintf(", %.2f", ratio)
+		}
+		report += "\n"
+	}
+	report += fmt.Sprintf("Sum of effective mass: X=%.2f%% Y=%.2f%% Z=%.2f%%\n",
+		sum[0], sum[1], sum[2])
+	return
+}
+
+// GetModeShape return shape of mode from result of modal analysis.
+// Index of first mode is zero.
+func (mm *Model) GetModeShape(mode uint) (ds [][6]float64) {
+	md := mm.results.modal
+	if md == nil || len(md.Shapes) <= int(mode) {
+		return nil
+	}
+	return md.Shapes[mode]
+}
+
+// Buckling run linear buckling analysis for lowest critical load factors
+func (mm *Model) Buckling(modes uint) (report string, err error) {
+	logger.Printf("Buckling")
+	mm.results.buckling = nil
+	fm, _, _, err := mm.femModel()
+	if err != nil {
+		logger.Printf("Buckling: %v", err)
+		return
+	}
+	bk, err := fm.Buckling(int(modes))
+	if err != nil {
+		logger.Printf("Buckling: %v", err)
+		return
+	}
+	mm.results.buckling = &bk
+	report = "Mode, Critical load factor\n"
+	for i, f := range bk.Factors {
+		report += fmt.Sprintf("%d, %.5g\n", i+1, f)
+	}
+	return
+}
+
+// GetBucklingShape return shape of buckling mode from result of linear
+// buckling analysis. Index of first mode is zero.
+func (mm *Model) GetBucklingShape(mode uint) (ds [][6]float64) {
+	bk := mm.results.buckling
+	if bk == nil || len(bk.Shapes) <= int(mode) {
+		return nil
+	}
+	return bk.Shapes[mode]
+}
+
+// Imperfection move coordinates by combination of buckling shapes:
+//
+//	offset = amplitude * (factors[0]*shape1 + factors[1]*shape2 + ...)
+//
+// Maximal translation of each buckling shape is 1.
+func (mm *Model) Imperfection(amplitude float64, factors []float64) (err error) {
+	logger.Printf("Imperfection")
+	bk := mm.results.buckling
+	if bk == nil {
+		err = fmt.Errorf("no results of linear buckling analysis")
+		return
+	}
+	if len(bk.Shapes) < len(factors) {
+		err = fmt.Errorf("amount of buckling shapes %d is less amount of factors %d",
+			len(bk.Shapes), len(factors))
+		return
+	}
+	for i := range mm.Coords {
+		if mm.Coords[i].Removed || len(bk.Shapes[0]) <= i {
+			continue
+		}
+		for m, f := range factors {
+			for d := 0; d < 3; d++ {
+				mm.Coords[i].Point3d[d] += amplitude * f * bk.Shapes[m][i][d]
+			}
+		}
+	}
+	// results is not valid for changed model
+	mm.results = results{}
+	return
+}
+
+// ResultField is scalar field for contour view
+type ResultField uint8
+
+const (
+	DisplacementField ResultField = iota // magnitude of translation
+	StressField                          // von Mises stress in plates
+	ThicknessField                       // thickness of plates
+	SectionAreaField                     // area of cross-section of lines
+	endResultField
+)
+
+func (f ResultField) String() string {
+	switch f {
+	case DisplacementField:
+		return "Displacement"
+	case StressField:
+		return "Stress"
+	case ThicknessField:
+		return "Thickness"
+	case SectionAreaField:
+		return "Section area"
+	}
+	return fmt.Sprintf("Undefined field %d", uint8(f))
+}
+
+// GetField return values of scalar field in nodes for nodal field or
+// in elements for element field. Not defined value is NaN.
+func (mm *Model) GetField(f ResultField) (nodes, elements []float64) {
+	undefined := func(n int) (vs []float64) {
+		vs = make([]float64, n)
+		for i := range vs {
+			vs[i] = math.NaN()
+		}
+		return
+	}
+	switch f {
+	case DisplacementField, StressField:
+		st := mm.results.static
+		if st == nil || len(st.Displacements) != len(mm.Coords) {
+			return
+		}
+		nodes = undefined(len(mm.Coords))
+		for i, d := range st.Displacements {
+			if f == DisplacementField {
+				nodes[i] = math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+			}
+		}
+		if f == StressField {
+			for _, id := range st.plates {
+				for _, p := range mm.Elements[id].Indexes {
+					nodes[p] = st.Stress[p]
+				}
+			}
+		}
+	case ThicknessField, SectionAreaField:
+		elements = undefined(len(mm.Elements))
+		walkGroups(mm.GetRootGroup(), func(gr groups.Group) {
+			switch g := gr.(type) {
+			case *groups.Thickness:
+				if f != ThicknessField {
+					return
+				}
+				for _, id := range g.Elements {
+					if int(id) < len(elements) {
+						elements[id] = g.Thickness
+					}
+				}
+			case *groups.Section:
+				if f != SectionAreaField {
+					return
+				}
+				for _, id := range g.Elements {
+					if int(id) < len(elements) {
+						elements[id] = g.A
+					}
+				}
+			}
+		})
+	}
+	return
+}
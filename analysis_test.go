@@ -0,0 +1,240 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// cantilever return model of cantilever along axe X with load at free end
+func cantilever(P float64) (mm *Model) {
+	mm = new(Model)
+	var lines []uint
+	for i := 0; i < 4; i++ {
+		mm.AddNode(float64(i), 0, 0)
+	}
+	for i := 0; i < 3; i++ {
+		lines = append(lines, mm.AddLineByNodeNumber(uint(i), uint(i+1)))
+	}
+	var (
+		mat groups.Material
+		sec groups.Section
+		sup groups.NodeSupports
+		nl  groups.NodeLoads
+	)
+	mat.E, mat.Nu = 2e11, 0.3
+	mat.Elements = lines
+	sec.A, sec.Iy, sec.Iz, sec.J = 1e-3, 2e-6, 1e-6, 5e-7
+	sec.Elements = lines
+	for d := range sup.Direction {
+		sup.Direction[d].Kind = groups.Fixed
+	}
+	sup.Nodes = []uint{0}
+	nl.Forces = [6]float64{0, 0, -P, 0, 0, 0}
+	nl.Nodes = []uint{3}
+	mm.Groups.meta.Groups = append(mm.Groups.meta.Groups, &mat, &sec, &sup, &nl)
+	return
+}
+
+func TestLinearStatic(t *testing.T) {
+	const P = 1000.0
+	mm := cantilever(P)
+	report, err := mm.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	ds := mm.GetDisplacements()
+	if len(ds) != 4 {
+		t.Fatalf("not valid amount of displacements: %d", len(ds))
+	}
+	expect := -P * 27 / (3 * 2e11 * 2e-6)
+	if math.Abs(ds[3][2]-expect) > 1e-6*math.Abs(expect) {
+		t.Errorf("not valid displacement: %e != %e", ds[3][2], expect)
+	}
+	table, err := mm.StaticTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"Displacement,3,",
+		"Reaction,0,0.000000e+00,0.000000e+00,1.000000e+03,",
+		"Force,2,3,",
+	} {
+		if !strings.Contains(table, s) {
+			t.Errorf("not found `%s` in table:\n%s", s, table)
+		}
+	}
+	// change of groups
+	mm.Update(nil, nil)
+	if _, err := mm.StaticTable(); err == nil {
+		t.Errorf("results for changed groups")
+	}
+}
+
+func TestLinearStaticWithoutSection(t *testing.T) {
+	mm := cantilever(1)
+	mm.Groups.meta.Groups = mm.Groups.meta.Groups[:1]
+	if _, err := mm.LinearStatic(); err == nil {
+		t.Fatalf("not found error")
+	}
+	if ds := mm.GetDisplacements(); ds != nil {
+		t.Fatalf("results without analysis")
+	}
+}
+
+func TestLinearStaticPlate(t *testing.T) {
+	// cantilever plate 2 x 1 with load at free edge
+	const P = 1000.0
+	mm := new(Model)
+	for i := 0; i <= 2; i++ {
+		for j := 0; j <= 4; j++ {
+			mm.AddNode(float64(j)/2, float64(i)/2, 0)
+		}
+	}
+	var plates []uint
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 4; j++ {
+			n := uint(i*5 + j)
+			id, ok := mm.AddQuadr4ByNodeNumber(n, n+1, n+6, n+5)
+			if !ok {
+				t.Fatalf("quadr4 is not added")
+			}
+			plates = append(plates, id)
+		}
+	}
+	var (
+		mat groups.Material
+		th  groups.Thickness
+		sup groups.NodeSupports
+		nl  groups.NodeLoads
+	)
+	mat.E, mat.Nu = 2e11, 0.3
+	mat.Elements = plates
+	th.Thickness = 0.02
+	th.Elements = plates
+	for d := range sup.Direction {
+		sup.Direction[d].Kind = groups.Fixed
+	}
+	sup.Nodes = []uint{0, 5, 10}
+	nl.Forces = [6]float64{0, 0, -P / 3, 0, 0, 0}
+	nl.Nodes = []uint{4, 9, 14}
+	mm.Groups.meta.Groups = append(mm.Groups.meta.Groups, &mat, &th, &sup, &nl)
+	report, err := mm.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	// beam theory with plate stiffness
+	D := 2e11 * 8e-6 / 12
+	expect := -P * 8 / (3 * D)
+	if dz := mm.GetDisplacements()[9][2]; math.Abs(dz-expect) > 0.1*math.Abs(expect) {
+		t.Errorf("not valid displacement: %e != %e", dz, expect)
+	}
+	table, err := mm.StaticTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"Plate,0,0,", "Stress,"} {
+		if !strings.Contains(table, s) {
+			t.Errorf("not found `%s` in table:\n%s", s, table)
+		}
+	}
+}
+
+func TestModal(t *testing.T) {
+	mm := cantilever(0)
+	for _, gr := range mm.Groups.meta.Groups {
+		if mat, ok := gr.(*groups.Material); ok {
+			mat.Density = 7850
+		}
+	}
+	var nm groups.NodeMasses
+	nm.Mass = 100
+	nm.Nodes = []uint{3}
+	mm.Groups.meta.Groups = append(mm.Groups.meta.Groups, &nm)
+	report, err := mm.Modal(3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if !strings.Contains(report, "Total mass: X=118.32") {
+		t.Errorf("not valid total mass")
+	}
+	if ds := mm.GetModeShape(2); len(ds) != 4 {
+		t.Errorf("not valid mode shape")
+	}
+	if ds := mm.GetModeShape(3); ds != nil {
+		t.Errorf("not valid mode shape")
+	}
+}
+
+func TestBucklingImperfection(t *testing.T) {
+	// cantilever column with compression along axe X
+	const P = 1000.0
+	mm := cantilever(0)
+	for _, gr := range mm.Groups.meta.Groups {
+		if nl, ok := gr.(*groups.NodeLoads); ok {
+			nl.Forces = [6]float64{-P, 0, 0, 0, 0, 0}
+		}
+	}
+	if err := mm.Imperfection(0.01, []float64{1}); err == nil {
+		t.Fatalf("imperfection without buckling analysis")
+	}
+	report, err := mm.Buckling(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	euler := math.Pi * math.Pi * 2e11 * 1e-6 / (4 * 9) / P
+	if !strings.Contains(report, fmt.Sprintf("1, %.4g", euler)) {
+		t.Errorf("not valid critical load factor %.5g", euler)
+	}
+	if err = mm.Imperfection(0.01, []float64{0.75, 0.25, 1}); err == nil {
+		t.Fatalf("not enough buckling shapes")
+	}
+	if err = mm.Imperfection(0.01, []float64{0.75, 0.25}); err != nil {
+		t.Fatal(err)
+	}
+	// free end is moved by first shape along Y and second along Z
+	c := mm.Coords[3].Point3d
+	if math.Abs(math.Abs(c[1])-0.0075) > 1e-9 || math.Abs(math.Abs(c[2])-0.0025) > 1e-9 {
+		t.Errorf("not valid imperfection: %v", c)
+	}
+	if mm.GetBucklingShape(0) != nil {
+		t.Errorf("results for changed model")
+	}
+}
+
+func TestGetField(t *testing.T) {
+	mm := cantilever(1000)
+	if nodes, _ := mm.GetField(DisplacementField); nodes != nil {
+		t.Fatalf("field without analysis")
+	}
+	if _, err := mm.LinearStatic(); err != nil {
+		t.Fatal(err)
+	}
+	nodes, elements := mm.GetField(DisplacementField)
+	if len(nodes) != 4 || elements != nil {
+		t.Fatalf("not valid field")
+	}
+	if nodes[0] != 0 || nodes[3] <= nodes[2] {
+		t.Errorf("not valid displacements: %v", nodes)
+	}
+	// without plates
+	nodes, _ = mm.GetField(StressField)
+	for _, v := range nodes {
+		if !math.IsNaN(v) {
+			t.Errorf("stress without plates")
+		}
+	}
+	_, elements = mm.GetField(SectionAreaField)
+	for _, v := range elements {
+		if v != 1e-3 {
+			t.Errorf("not valid section area: %v", elements)
+		}
+	}
+}
@@ -0,0 +1,555 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// Structure is connected part of model
+type Structure struct {
+	Nodes    []uint
+	Elements []uint
+}
+
+// isElement return true for element with acceptable type and not
+// removed nodes
+func (mm *Model) isElement(el Element) bool {
+	if el.ElementType == ElRemove || el.Check() != nil {
+		return false
+	}
+	for _, p := range el.Indexes {
+		if p < 0 || len(mm.Coords) <= p || mm.Coords[p].Removed {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckSingleStructure return connected parts of model sorted by amount
+// of elements. Nodes is connected by elements and rigid links. Free nodes
+// without elements is not part of any structure. Model is single
+// structure, if amount of parts is 1.
+func (mm *Model) CheckSingleStructure() (parts []Structure) {
+	logger.Printf("CheckSingleStructure")
+	// union-find of nodes
+	parent := make([]int, len(mm.Coords))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		if a, b = find(a), find(b); a != b {
+			parent[b] = a
+		}
+	}
+	isNode := func(id int) bool {
+		return 0 <= id && id < len(mm.Coords) && !mm.Coords[id].Removed
+	}
+	used := make([]bool, len(mm.Coords))
+	for _, el := range mm.Elements {
+		if !mm.isElement(el) {
+			continue
+		}
+		for _, p := range el.Indexes {
+			if !isNode(p) {
+				continue
+			}
+			used[p] = true
+			union(el.Indexes[0], p)
+		}
+	}
+	walkGroups(mm.GetRootGroup(), func(gr groups.Group) {
+		g, ok := gr.(*groups.RigidLinks)
+		if !ok || !isNode(int(g.Master)) {
+			return
+		}
+		for _, s := range g.Nodes {
+			if !isNode(int(s)) {
+				continue
+			}
+			used[g.Master], used[s] = true, true
+			union(int(g.Master), int(s))
+		}
+	})
+	index := map[int]int{} // root node to index of part
+	part := func(root int) *Structure {
+		i, ok := index[root]
+		if !ok {
+			i = len(parts)
+			index[root] = i
+			parts = append(parts, Structure{})
+		}
+		return &parts[i]
+	}
+	for i := range mm.Coords {
+		if !used[i] {
+			continue
+		}
+		p := part(find(i))
+		p.Nodes = append(p.Nodes, uint(i))
+	}
+	for i, el := range mm.Elements {
+		if !mm.isElement(el) {
+			continue
+		}
+		p := part(find(el.Indexes[0]))
+		p.Elements = append(p.Elements, uint(i))
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		if len(parts[i].Elements) != len(parts[j].Elements) {
+			return len(parts[i].Elements) > len(parts[j].Elements)
+		}
+		return len(parts[i].Nodes) > len(parts[j].Nodes)
+	})
+	return
+}
+
+// CheckDuplicateElements return groups of elements with same type and
+// same nodes in any order. Each group is sorted by element id.
+func (mm *Model) CheckDuplicateElements() (duplicates [][]uint) {
+	logger.Printf("CheckDuplicateElements")
+	index := map[string]int{} // key of element to index of group
+	for i, el := range mm.Elements {
+		if el.ElementType == ElRemove || len(el.Indexes) == 0 {
+			continue
+		}
+		ids := append([]int(nil), el.Indexes...)
+		sort.Ints(ids)
+		key := fmt.Sprint(el.ElementType, ids)
+		g, ok := index[key]
+		if !ok {
+			index[key] = len(duplicates)
+			duplicates = append(duplicates, []uint{uint(i)})
+			continue
+		}
+		duplicates[g] = append(duplicates[g], uint(i))
+	}
+	// remove unique elements
+	size := 0
+	for _, d := range duplicates {
+		if len(d) < 2 {
+			continue
+		}
+		duplicates[size] = d
+		size++
+	}
+	duplicates = duplicates[:size]
+	return
+}
+
+// RemoveDuplicateElements remove all duplicate elements except element
+// with minimal id in each group
+func (mm *Model) RemoveDuplicateElements() {
+	var remove []uint
+	for _, d := range mm.CheckDuplicateElements() {
+		remove = append(remove, d[1:]...)
+	}
+	if len(remove) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll()
+	mm.Remove(nil, remove)
+}
+
+// lineParameter return distance from point to infinite line and
+// position of point projection on line from begin point
+func lineParameter(l0, l1, p gog.Point3d) (distance, position float64) {
+	d, v := vector(l0, l1), vector(l0, p)
+	L := norm(d)
+	return norm(cross(d, v)) / L, dot(d, v) / L
+}
+
+// CheckLinesOverlapping return pairs of collinear lines with common part
+// of not zero length. Pairs of duplicate lines are ignored.
+func (mm *Model) CheckLinesOverlapping() (pairs [][2]uint) {
+	logger.Printf("CheckLinesOverlapping")
+	var lines []uint
+	for i, el := range mm.Elements {
+		if el.ElementType != Line2 || !mm.isElement(el) || gog.ZeroLine3d(
+			mm.Coords[el.Indexes[0]].Point3d,
+			mm.Coords[el.Indexes[1]].Point3d,
+		) {
+			continue
+		}
+		lines = append(lines, uint(i))
+	}
+	for i, a := range lines {
+		ea := mm.Elements[a].Indexes
+		a0, a1 := mm.Coords[ea[0]].Point3d, mm.Coords[ea[1]].Point3d
+		L := gog.Distance3d(a0, a1)
+		for _, b := range lines[i+1:] {
+			eb := mm.Elements[b].Indexes
+			if (ea[0] == eb[0] && ea[1] == eb[1]) || (ea[0] == eb[1] && ea[1] == eb[0]) {
+				// duplicate
+				continue
+			}
+			d0, t0 := lineParameter(a0, a1, mm.Coords[eb[0]].Point3d)
+			d1, t1 := lineParameter(a0, a1, mm.Coords[eb[1]].Point3d)
+			if gog.Eps3D < d0 || gog.Eps3D < d1 {
+				// not collinear
+				continue
+			}
+			if math.Min(L, math.Max(t0, t1))-math.Max(0, math.Min(t0, t1)) < gog.Eps3D {
+				// without common part
+				continue
+			}
+			pairs = append(pairs, [2]uint{a, b})
+		}
+	}
+	return
+}
+
+// FixLinesOverlapping split overlapping lines by end nodes of each
+// other and remove duplicate lines. Result is chain of lines without
+// overlapping. Parts of line are in same groups as line.
+func (mm *Model) FixLinesOverlapping() {
+	pairs := mm.CheckLinesOverlapping()
+	if len(pairs) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll()
+	// nodes for split of each line
+	split := map[uint][]int{}
+	for _, p := range pairs {
+		for k := range p {
+			a, b := p[k], p[1-k]
+			split[a] = append(split[a], mm.Elements[b].Indexes...)
+		}
+	}
+	lines := make([]uint, 0, len(split))
+	for l := range split {
+		lines = append(lines, l)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i] < lines[j] })
+	for _, l := range lines {
+		b, e := mm.Elements[l].Indexes[0], mm.Elements[l].Indexes[1]
+		p0, p1 := mm.Coords[b].Point3d, mm.Coords[e].Point3d
+		L := gog.Distance3d(p0, p1)
+		type point struct {
+			node     int
+			position float64
+		}
+		var ps []point
+		for _, n := range split[l] {
+			_, t := lineParameter(p0, p1, mm.Coords[n].Point3d)
+			if t < gog.Eps3D || L-gog.Eps3D < t {
+				// point outside line or on end of line
+				continue
+			}
+			ps = append(ps, point{node: n, position: t})
+		}
+		sort.Slice(ps, func(i, j int) bool { return ps[i].position < ps[j].position })
+		ids := []int{b}
+		for _, p := range ps {
+			if ids[len(ids)-1] == p.node {
+				continue
+			}
+			ids = append(ids, p.node)
+		}
+		ids = append(ids, e)
+		mm.Elements[l].Indexes[1] = ids[1]
+		var parts []uint
+		for i := 2; i < len(ids); i++ {
+			parts = append(parts, mm.AddLineByNodeNumber(uint(ids[i-1]), uint(ids[i])))
+		}
+		mm.copyGroups(l, parts)
+	}
+	mm.RemoveDuplicateElements()
+}
+
+// PlateDefect is type of not valid geometry of plate
+type PlateDefect uint8
+
+const (
+	CollinearTriangle PlateDefect = iota // triangle nodes on one line
+	WarpedQuadr                          // quadrilateral is not flat
+	ConcaveQuadr                         // quadrilateral with concave corner
+	BowTieQuadr                          // self-intersected quadrilateral
+	InvertedNormal                       // normal opposite to neighbors
+	endPlateDefect
+)
+
+func (p PlateDefect) String() string {
+	switch p {
+	case CollinearTriangle:
+		return "Collinear triangles"
+	case WarpedQuadr:
+		return "Warped quadrilaterals"
+	case ConcaveQuadr:
+		return "Concave quadrilaterals"
+	case BowTieQuadr:
+		return "Bow-tie quadrilaterals"
+	case InvertedNormal:
+		return "Inverted normals"
+	}
+	return fmt.Sprintf("Undefined:%02d", p)
+}
+
+// quadrWarping return warping angle of quadrilateral in degrees as
+// minimal angle between normals of triangles by diagonals. Angle of
+// concave quadrilateral is by diagonal inside quadrilateral.
+func quadrWarping(ps [4]gog.Point3d) (angle float64) {
+	angle = 180
+	for d := 0; d < 2; d++ {
+		a, b, c, e := ps[d], ps[d+1], ps[d+2], ps[(d+3)%4]
+		n1 := cross(vector(a, b), vector(a, c))
+		n2 := cross(vector(a, c), vector(a, e))
+		l := norm(n1) * norm(n2)
+		if l == 0 {
+			continue
+		}
+		cos := math.Max(-1, math.Min(1, dot(n1, n2)/l))
+		angle = math.Min(angle, math.Acos(cos)*180/math.Pi)
+	}
+	return
+}
+
+// quadrNegativeCorners return amount of corners of quadrilateral with
+// turn opposite to quadrilateral normal. Convex quadrilateral is without
+// negative corners, concave have 1 and bow-tie have 2.
+func quadrNegativeCorners(ps [4]gog.Point3d) (negatives int) {
+	// Newell normal
+	var n [3]float64
+	for i := range ps {
+		c := cross(ps[i], ps[(i+1)%4])
+		for k := range n {
+			n[k] += c[k]
+		}
+	}
+	for i := 0; i < 2 && norm(n) < gog.Eps3D*gog.Eps3D; i++ {
+		// symmetric bow-tie
+		n = cross(vector(ps[i], ps[i+1]), vector(ps[i], ps[i+2]))
+	}
+	for i := range ps {
+		prev, next := ps[(i+3)%4], ps[(i+1)%4]
+		if dot(cross(vector(prev, ps[i]), vector(ps[i], next)), n) < 0 {
+			negatives++
+		}
+	}
+	return
+}
+
+// CheckPlates return plates with not valid geometry for each type of
+// defect. Warping is maximal angle in degrees between normals of
+// triangles of quadrilateral. Inverted normals is minimal part of
+// plates with orientation opposite to connected plates.
+func (mm *Model) CheckPlates(warping float64) (defects [endPlateDefect][]uint) {
+	logger.Printf("CheckPlates")
+	points := func(el Element) (ps []gog.Point3d) {
+		for _, p := range el.Indexes {
+			ps = append(ps, mm.Coords[p].Point3d)
+		}
+		return
+	}
+	var plates []uint
+	for i, el := range mm.Elements {
+		if !mm.isElement(el) {
+			continue
+		}
+		switch el.ElementType {
+		case Triangle3:
+			ps := points(el)
+			if gog.ZeroTriangle3d(ps[0], ps[1], ps[2]) {
+				defects[CollinearTriangle] = append(defects[CollinearTriangle], uint(i))
+			}
+		case Quadr4:
+			ps := [4]gog.Point3d(points(el))
+			switch quadrNegativeCorners(ps) {
+			case 2:
+				defects[BowTieQuadr] = append(defects[BowTieQuadr], uint(i))
+				plates = append(plates, uint(i))
+				continue
+			case 1, 3:
+				defects[ConcaveQuadr] = append(defects[ConcaveQuadr], uint(i))
+			}
+			if warping < quadrWarping(ps) {
+				defects[WarpedQuadr] = append(defects[WarpedQuadr], uint(i))
+			}
+		default:
+			continue
+		}
+		plates = append(plates, uint(i))
+	}
+	// plates on each edge with direction of edge in plate
+	type side struct {
+		plate   uint
+		forward bool
+	}
+	edges := map[[2]int][]side{}
+	for _, p := range plates {
+		ids := mm.Elements[p].Indexes
+		for k := range ids {
+			a, b := ids[k], ids[(k+1)%len(ids)]
+			key, forward := [2]int{a, b}, true
+			if b < a {
+				key, forward = [2]int{b, a}, false
+			}
+			edges[key] = append(edges[key], side{plate: p, forward: forward})
+		}
+	}
+	// orientation of plates relative to first plate of connected plates
+	flip := map[uint]bool{}
+	for _, first := range plates {
+		if _, ok := flip[first]; ok {
+			continue
+		}
+		flip[first] = false
+		component, queue := []uint{first}, []uint{first}
+		for len(queue) != 0 {
+			p := queue[0]
+			queue = queue[1:]
+			ids := mm.Elements[p].Indexes
+			for k := range ids {
+				a, b := ids[k], ids[(k+1)%len(ids)]
+				if b < a {
+					a, b = b, a
+				}
+				sides := edges[[2]int{a, b}]
+				if len(sides) != 2 {
+					// free or not manifold edge
+					continue
+				}
+				s1, s2 := sides[0], sides[1]
+				if s2.plate == p {
+					s1, s2 = s2, s1
+				}
+				if _, ok := flip[s2.plate]; ok {
+					continue
+				}
+				// consistent orientation for opposite direction of edge
+				flip[s2.plate] = flip[p] != (s1.forward == s2.forward)
+				component = append(component, s2.plate)
+				queue = append(queue, s2.plate)
+			}
+		}
+		var inverted, other []uint
+		for _, p := range component {
+			if flip[p] {
+				inverted = append(inverted, p)
+			} else {
+				other = append(other, p)
+			}
+		}
+		if len(other) < len(inverted) {
+			inverted = other
+		}
+		defects[InvertedNormal] = append(defects[InvertedNormal], inverted...)
+	}
+	sort.Slice(defects[InvertedNormal], func(i, j int) bool {
+		return defects[InvertedNormal][i] < defects[InvertedNormal][j]
+	})
+	return
+}
+
+// Severity of check finding
+type Severity uint8
+
+const (
+	SeverityError   Severity = iota // model is not valid for analysis
+	SeverityWarning                 // model is valid, but not recommended
+	SeverityInfo                    // information only
+	endSeverity
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "Error"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityInfo:
+		return "Info"
+	}
+	return fmt.Sprintf("Undefined:%02d", s)
+}
+
+// Finding is result of model check with affected nodes and elements
+type Finding struct {
+	Severity Severity
+	Message  string
+	Nodes    []uint
+	Elements []uint
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Severity, f.Message)
+}
+
+// defaultWarping is tolerance of warping angle of quadrilaterals in
+// degrees for check of model
+const defaultWarping = 5.0
+
+// CheckAll return findings of all checks of model sorted by severity.
+// Geometric checks is not run for model with not valid structure.
+func (mm *Model) CheckAll() (findings []Finding) {
+	logger.Printf("CheckAll")
+	findings = mm.validity()
+	if len(findings) != 0 {
+		return
+	}
+	add := func(s Severity, nodes, elements []uint, format string, a ...any) {
+		findings = append(findings, Finding{
+			Severity: s,
+			Message:  fmt.Sprintf(format, a...),
+			Nodes:    nodes,
+			Elements: elements,
+		})
+	}
+	for _, d := range mm.CheckDuplicateElements() {
+		add(SeverityWarning, nil, d, "Duplicate elements %v", d)
+	}
+	for _, p := range mm.CheckLinesOverlapping() {
+		add(SeverityWarning, nil, p[:], "Overlapping lines %d and %d", p[0], p[1])
+	}
+	defects := mm.CheckPlates(defaultWarping)
+	for d := range defects {
+		s := SeverityWarning
+		switch PlateDefect(d) {
+		case CollinearTriangle, BowTieQuadr:
+			s = SeverityError
+		}
+		for _, e := range defects[d] {
+			add(s, nil, []uint{e}, "%s: element %d", PlateDefect(d), e)
+		}
+	}
+	parts := mm.CheckSingleStructure()
+	for i := 1; i < len(parts); i++ {
+		add(SeverityWarning, parts[i].Nodes, parts[i].Elements,
+			"Disconnected part %d: %d nodes, %d elements",
+			i+1, len(parts[i].Nodes), len(parts[i].Elements))
+	}
+	var free []uint
+	used := make([]bool, len(mm.Coords))
+	for _, el := range mm.Elements {
+		if el.ElementType == ElRemove {
+			continue
+		}
+		for _, p := range el.Indexes {
+			used[p] = true
+		}
+	}
+	for i := range mm.Coords {
+		if !used[i] && !mm.Coords[i].Removed {
+			free = append(free, uint(i))
+		}
+	}
+	if len(free) != 0 {
+		add(SeverityInfo, free, nil, "Nodes without elements: %d", len(free))
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity < findings[j].Severity
+	})
+	return
+}
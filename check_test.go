@@ -0,0 +1,222 @@
+package ms
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/Konstantin8105/ms/groups"
+)
+
+func TestCheckSingleStructure(t *testing.T) {
+	var mm Model
+	// part with 2 lines
+	for i := 0; i < 3; i++ {
+		mm.AddNode(float64(i), 0, 0)
+	}
+	mm.AddLineByNodeNumber(0, 1)
+	mm.AddLineByNodeNumber(1, 2)
+	if parts := mm.CheckSingleStructure(); len(parts) != 1 {
+		t.Fatalf("not single structure: %v", parts)
+	}
+	// line with gap
+	mm.AddNode(2.1, 0, 0)
+	mm.AddNode(3, 0, 0)
+	mm.AddLineByNodeNumber(3, 4)
+	// free node
+	mm.AddNode(5, 5, 5)
+	parts := mm.CheckSingleStructure()
+	if len(parts) != 2 {
+		t.Fatalf("not valid amount of parts: %v", parts)
+	}
+	if len(parts[0].Elements) != 2 || len(parts[0].Nodes) != 3 ||
+		len(parts[1].Elements) != 1 || len(parts[1].Nodes) != 2 {
+		t.Errorf("not valid parts: %v", parts)
+	}
+	// rigid link connect parts
+	var rl groups.RigidLinks
+	rl.Master = 2
+	rl.Nodes = []uint{3}
+	mm.Groups.meta.Groups = append(mm.Groups.meta.Groups, &rl)
+	if parts := mm.CheckSingleStructure(); len(parts) != 1 {
+		t.Fatalf("rigid link is not connection: %v", parts)
+	}
+	// not valid elements are ignored
+	mm.Elements = append(mm.Elements,
+		Element{ElementType: Line2},
+		Element{ElementType: Line2, Indexes: []int{0, 100}},
+		Element{ElementType: Triangle3, Indexes: []int{0, 1}},
+	)
+	if parts := mm.CheckSingleStructure(); len(parts) != 1 || len(parts[0].Elements) != 3 {
+		t.Fatalf("not valid elements: %v", parts)
+	}
+}
+
+func TestCheckDuplicateElements(t *testing.T) {
+	var mm Model
+	for i := 0; i < 3; i++ {
+		mm.AddNode(float64(i), 0, 0)
+	}
+	mm.AddNode(0, 1, 0)
+	mm.AddLineByNodeNumber(0, 1)
+	mm.AddTriangle3ByNodeNumber(0, 1, 3)
+	mm.Elements = append(mm.Elements,
+		Element{ElementType: Line2, Indexes: []int{1, 0}},
+		Element{ElementType: Triangle3, Indexes: []int{3, 1, 0}},
+		Element{ElementType: Line2, Indexes: []int{1, 2}},
+	)
+	dups := mm.CheckDuplicateElements()
+	if len(dups) != 2 || len(dups[0]) != 2 || dups[0][1] != 2 || dups[1][1] != 3 {
+		t.Fatalf("not valid duplicates: %v", dups)
+	}
+	mm.RemoveDuplicateElements()
+	if dups := mm.CheckDuplicateElements(); len(dups) != 0 {
+		t.Fatalf("duplicates after remove: %v", dups)
+	}
+	if mm.Elements[0].ElementType != Line2 || mm.Elements[1].ElementType != Triangle3 ||
+		mm.Elements[2].ElementType != ElRemove || mm.Elements[3].ElementType != ElRemove {
+		t.Errorf("not valid elements: %v", mm.Elements)
+	}
+}
+
+func TestCheckLinesOverlapping(t *testing.T) {
+	var mm Model
+	for _, x := range []float64{0, 1, 2, 3} {
+		mm.AddNode(x, 0, 0)
+	}
+	mm.AddNode(1, 1, 0)
+	mm.AddLineByNodeNumber(0, 2) // 0 - 2
+	mm.AddLineByNodeNumber(1, 3) // 1 - 3
+	mm.AddLineByNodeNumber(1, 4) // not collinear
+	mm.AddLineByNodeNumber(2, 3) // inside line 1 - 3
+	pairs := mm.CheckLinesOverlapping()
+	if len(pairs) != 2 {
+		t.Fatalf("not valid pairs: %v", pairs)
+	}
+	mm.FixLinesOverlapping()
+	if pairs := mm.CheckLinesOverlapping(); len(pairs) != 0 {
+		t.Fatalf("overlapping after fix: %v", pairs)
+	}
+	// chain 0-1, 1-2, 2-3 and line 1-4
+	var lines [][2]int
+	for _, el := range mm.Elements {
+		if el.ElementType != Line2 {
+			continue
+		}
+		a, b := el.Indexes[0], el.Indexes[1]
+		if b < a {
+			a, b = b, a
+		}
+		lines = append(lines, [2]int{a, b})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][0]*10+lines[i][1] < lines[j][0]*10+lines[j][1] })
+	if fmt.Sprint(lines) != "[[0 1] [1 2] [1 4] [2 3]]" {
+		t.Errorf("not valid lines: %v", lines)
+	}
+}
+
+func TestCheckNotValidElements(t *testing.T) {
+	var mm Model
+	grid(&mm, 1)
+	mm.AddLineByNodeNumber(0, 1)
+	mm.Elements = append(mm.Elements,
+		Element{ElementType: Line2, Indexes: []int{0, 100}},
+		Element{ElementType: Triangle3, Indexes: []int{0, 1, -1}},
+		Element{ElementType: Quadr4, Indexes: []int{0, 1}},
+	)
+	if pairs := mm.CheckLinesOverlapping(); len(pairs) != 0 {
+		t.Errorf("not valid pairs: %v", pairs)
+	}
+	for d, ps := range mm.CheckPlates(defaultWarping) {
+		if len(ps) != 0 {
+			t.Errorf("not valid defect %v: %v", PlateDefect(d), ps)
+		}
+	}
+}
+
+func TestFixLinesOverlappingGroups(t *testing.T) {
+	mm := cantilever(1000)
+	// line overlapping with end line 2-3 of cantilever
+	l := mm.AddLineByNodeNumber(mm.AddNode(2.5, 0, 0), mm.AddNode(3.5, 0, 0))
+	for _, gr := range mm.Groups.meta.Groups {
+		switch g := gr.(type) {
+		case *groups.Material:
+			g.Elements = append(g.Elements, l)
+		case *groups.Section:
+			g.Elements = append(g.Elements, l)
+		}
+	}
+	mm.FixLinesOverlapping()
+	if pairs := mm.CheckLinesOverlapping(); len(pairs) != 0 {
+		t.Fatalf("overlapping after fix: %v", pairs)
+	}
+	if _, _, _, err := mm.femModel(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mm.LinearStatic(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckPlates(t *testing.T) {
+	var mm Model
+	for _, c := range [][3]float64{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}, // 0-3
+		{2, 0, 0}, {2, 1, 0.5}, // 4-5: warped
+		{5, 0, 0}, {6, 0, 0}, {6, 1, 0}, {5, 1, 0}, // 6-9: bow-tie
+		{0, 3, 0}, {2, 3, 0}, {1, 3.5, 0}, {1, 5, 0}, // 10-13: concave
+		{0, 6, 0}, {1, 6, 0}, {2, 6, 0}, // 14-16: collinear
+		{-1, 0.5, 0}, // 17
+	} {
+		mm.AddNode(c[0], c[1], c[2])
+	}
+	mm.Elements = append(mm.Elements,
+		Element{ElementType: Quadr4, Indexes: []int{0, 1, 2, 3}},     // 0: valid
+		Element{ElementType: Quadr4, Indexes: []int{1, 4, 5, 2}},     // 1: warped
+		Element{ElementType: Quadr4, Indexes: []int{6, 8, 7, 9}},     // 2: bow-tie
+		Element{ElementType: Quadr4, Indexes: []int{10, 11, 12, 13}}, // 3: concave
+		Element{ElementType: Triangle3, Indexes: []int{14, 15, 16}},  // 4: collinear
+		Element{ElementType: Triangle3, Indexes: []int{3, 0, 17}},    // 5: inverted
+	)
+	defects := mm.CheckPlates(5)
+	for d, exp := range map[PlateDefect]string{
+		CollinearTriangle: "[4]",
+		WarpedQuadr:       "[1]",
+		ConcaveQuadr:      "[3]",
+		BowTieQuadr:       "[2]",
+		InvertedNormal:    "[5]",
+	} {
+		if act := fmt.Sprint(defects[d]); act != exp {
+			t.Errorf("%s: %s != %s", d, act, exp)
+		}
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	var mm Model
+	if fs := mm.CheckAll(); len(fs) != 0 {
+		t.Fatalf("findings of empty model: %v", fs)
+	}
+	for i := 0; i < 3; i++ {
+		mm.AddNode(float64(i), 0, 0)
+	}
+	mm.AddNode(5, 5, 5)
+	mm.AddLineByNodeNumber(0, 1)
+	mm.AddLineByNodeNumber(1, 2)
+	mm.Elements = append(mm.Elements, Element{ElementType: Line2, Indexes: []int{1, 0}})
+	fs := mm.CheckAll()
+	if len(fs) != 2 ||
+		fs[0].Severity != SeverityWarning || fmt.Sprint(fs[0].Elements) != "[0 2]" ||
+		fs[1].Severity != SeverityInfo || fmt.Sprint(fs[1].Nodes) != "[3]" {
+		t.Fatalf("not valid findings: %v", fs)
+	}
+	// not valid structure
+	mm.Elements = append(mm.Elements, Element{ElementType: Line2, Indexes: []int{1, 10}})
+	fs = mm.CheckAll()
+	if len(fs) != 1 || fs[0].Severity != SeverityError || fmt.Sprint(fs[0].Elements) != "[3]" {
+		t.Fatalf("not valid findings: %v", fs)
+	}
+	if mm.Check() == nil {
+		t.Fatalf("not valid model")
+	}
+}
@@ -0,0 +1,514 @@
+package ms
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Konstantin8105/gog"
+)
+
+// chainNodes return nodes of lines connected in one chain. Amount of
+// nodes is amount of lines plus one and for closed chain last node is
+// same as first. Result is not valid for branched or not connected lines.
+func (mm *Model) chainNodes(lines []uint) (nodes []uint, ok bool) {
+	lines = uniqUint(append([]uint(nil), lines...))
+	if len(lines) == 0 {
+		return
+	}
+	degree := map[int]int{}
+	for _, l := range lines {
+		for _, p := range mm.Elements[l].Indexes {
+			degree[p]++
+		}
+	}
+	start := mm.Elements[lines[0]].Indexes[0]
+	for _, l := range lines {
+		for _, p := range mm.Elements[l].Indexes {
+			if 2 < degree[p] {
+				return nil, false
+			}
+			if degree[start] == 2 && degree[p] == 1 {
+				start = p
+			}
+		}
+	}
+	used := make([]bool, len(lines))
+	nodes = append(nodes, uint(start))
+	for present := start; len(nodes) <= len(lines); {
+		found := false
+		for i, l := range lines {
+			idx := mm.Elements[l].Indexes
+			if used[i] || (idx[0] != present && idx[1] != present) {
+				continue
+			}
+			used[i], found = true, true
+			if idx[0] == present {
+				present = idx[1]
+			} else {
+				present = idx[0]
+			}
+			nodes = append(nodes, uint(present))
+			break
+		}
+		if !found {
+			// not connected lines
+			return nil, false
+		}
+	}
+	return nodes, true
+}
+
+// pathChain return nodes of chain of path lines from end nearest to
+// point
+func (mm *Model) pathChain(path []uint, p gog.Point3d) (chain []uint, ok bool) {
+	chain, ok = mm.chainNodes(path)
+	if !ok {
+		return
+	}
+	first, last := mm.Coords[chain[0]].Point3d, mm.Coords[chain[len(chain)-1]].Point3d
+	if gog.Distance3d(p, last) < gog.Distance3d(p, first) {
+		for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+			chain[i], chain[j] = chain[j], chain[i]
+		}
+	}
+	return
+}
+
+// extrude create plates from lines and hexahedrons from quadrilaterals
+// between layers of nodes. Offsets is movement of each layer from
+// position of elements.
+func (mm *Model) extrude(elements []uint, offsets [][3]float64) (created []uint) {
+	var els []uint
+	var nodes []uint
+	for _, e := range uniqUint(append([]uint(nil), elements...)) {
+		if t := mm.Elements[e].ElementType; t != Line2 && t != Quadr4 {
+			continue
+		}
+		els = append(els, e)
+		for _, p := range mm.Elements[e].Indexes {
+			nodes = append(nodes, uint(p))
+		}
+	}
+	nodes = uniqUint(nodes)
+	if len(els) == 0 {
+		return
+	}
+	// nodes of layers
+	layers := []map[int]int{{}}
+	for _, n := range nodes {
+		layers[0][int(n)] = int(n)
+	}
+	for _, off := range offsets {
+		layer := map[int]int{}
+		for _, n := range nodes {
+			p := mm.Coords[n].Point3d
+			layer[int(n)] = int(mm.AddNode(p[0]+off[0], p[1]+off[1], p[2]+off[2]))
+		}
+		layers = append(layers, layer)
+	}
+	// elements between layers
+	for k := 1; k < len(layers); k++ {
+		step := offsets[k-1]
+		if 1 < k {
+			step = vector(gog.Point3d(offsets[k-2]), gog.Point3d(offsets[k-1]))
+		}
+		b, t := layers[k-1], layers[k]
+		for _, e := range els {
+			idx := mm.Elements[e].Indexes
+			switch mm.Elements[e].ElementType {
+			case Line2:
+				id, ok := mm.AddQuadr4ByNodeNumber(
+					uint(b[idx[0]]), uint(b[idx[1]]),
+					uint(t[idx[1]]), uint(t[idx[0]]),
+				)
+				if ok {
+					created = append(created, id)
+				}
+			case Quadr4:
+				order := []int{idx[0], idx[1], idx[2], idx[3]}
+				if dot(plateNormal(mm.getPoint3d(e)), step) < 0 {
+					// normal of bottom face is inside of hexahedron
+					order = []int{idx[0], idx[3], idx[2], idx[1]}
+				}
+				var ns [8]uint
+				for i, p := range order {
+					ns[i], ns[i+4] = uint(b[p]), uint(t[p])
+				}
+				if id, ok := mm.AddHexa8ByNodeNumber(ns); ok {
+					created = append(created, id)
+				}
+			}
+		}
+	}
+	return
+}
+
+// Extrude create Quadr4 from lines and Hexa8 from quadrilaterals by
+// moving along vector with amount of parts
+func (mm *Model) Extrude(elements []uint, vector [3]float64, parts uint) (created []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("Extrude: not valid elements id: %v", s)
+		return
+	}
+	if norm(vector) < gog.Eps3D {
+		logger.Printf("Extrude: not valid vector: %v", vector)
+		return
+	}
+	if parts == 0 {
+		logger.Printf("Extrude: not valid amount of parts")
+		return
+	}
+	// actions
+	defer mm.DeselectAll() // deselect
+	var offsets [][3]float64
+	for k := uint(1); k <= parts; k++ {
+		var off [3]float64
+		for i := range off {
+			off[i] = vector[i] * float64(k) / float64(parts)
+		}
+		offsets = append(offsets, off)
+	}
+	return mm.extrude(elements, offsets)
+}
+
+// ExtrudeByPath create Quadr4 from lines and Hexa8 from quadrilaterals
+// by moving along chain of path lines. Each line of path is divided by
+// amount of parts. Path begin from end nearest to elements.
+func (mm *Model) ExtrudeByPath(elements, path []uint, parts uint) (created []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("ExtrudeByPath: not valid elements id: %v", s)
+		return
+	}
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := path; !mm.isValidElementId(s, isLine) {
+		logger.Printf("ExtrudeByPath: not valid path id: %v", s)
+		return
+	}
+	if parts == 0 {
+		logger.Printf("ExtrudeByPath: not valid amount of parts")
+		return
+	}
+	var ps []gog.Point3d
+	for _, e := range elements {
+		ps = append(ps, mm.getPoint3d(e)...)
+	}
+	chain, ok := mm.pathChain(path, centroid(ps))
+	if !ok {
+		logger.Printf("ExtrudeByPath: path is not chain of lines")
+		return
+	}
+	// actions
+	if len(ps) == 0 {
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	first := mm.Coords[chain[0]].Point3d
+	var offsets [][3]float64
+	for i := 1; i < len(chain); i++ {
+		from, to := mm.Coords[chain[i-1]].Point3d, mm.Coords[chain[i]].Point3d
+		for k := uint(1); k <= parts; k++ {
+			p := gog.PointLineRatio3d(from, to, float64(k)/float64(parts))
+			offsets = append(offsets, vector(first, p))
+		}
+	}
+	return mm.extrude(elements, offsets)
+}
+
+// addPlate add Quadr4 by nodes or 2 Triangle3 by shorter diagonal for
+// not flat quadrilateral
+func (mm *Model) addPlate(ns [4]uint) (ids []uint) {
+	if id, ok := mm.AddQuadr4ByNodeNumber(ns[0], ns[1], ns[2], ns[3]); ok {
+		return []uint{id}
+	}
+	for _, tr := range mm.quadrTriangles(ns) {
+		if id, ok := mm.AddTriangle3ByNodeNumber(tr[0], tr[1], tr[2]); ok {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// quadrTriangles return 2 triangles of quadrilateral by shorter diagonal
+func (mm *Model) quadrTriangles(ns [4]uint) [2][3]uint {
+	point := func(i int) gog.Point3d { return mm.Coords[ns[i]].Point3d }
+	if gog.Distance3d(point(1), point(3)) < gog.Distance3d(point(0), point(2)) {
+		// shift for diagonal from first node
+		ns = [4]uint{ns[1], ns[2], ns[3], ns[0]}
+	}
+	return [2][3]uint{{ns[0], ns[1], ns[2]}, {ns[0], ns[2], ns[3]}}
+}
+
+// isFlat return true for quadrilateral with all nodes on one plane
+func (mm *Model) isFlat(ns [4]uint) bool {
+	A, B, C, D := gog.Plane(
+		mm.Coords[ns[0]].Point3d,
+		mm.Coords[ns[1]].Point3d,
+		mm.Coords[ns[2]].Point3d,
+	)
+	return gog.PointOnPlane3d(A, B, C, D, mm.Coords[ns[3]].Point3d)
+}
+
+// Revolve create plates by rotation of lines around axis through 2 nodes
+// on angle in degrees with amount of segments. Quadr4 is created between
+// rotated lines or 2 Triangle3 for not flat quadrilateral and Triangle3
+// for lines with node on axis. Nodes on axis are not duplicated. Angle
+// is not more than full revolution and full revolution is with 3
+// segments at least.
+func (mm *Model) Revolve(lines []uint, axis [2]uint, angle float64, segments uint) (created []uint) {
+	// check
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := lines; !mm.isValidElementId(s, isLine) {
+		logger.Printf("Revolve: not valid lines id: %v", s)
+		return
+	}
+	if s := axis[:]; !mm.isValidNodeId(s) {
+		logger.Printf("Revolve: not valid axis nodes: %v", s)
+		return
+	}
+	origin := mm.Coords[axis[0]].Point3d
+	dir := vector(origin, mm.Coords[axis[1]].Point3d)
+	if norm(dir) < gog.Eps3D {
+		logger.Printf("Revolve: not valid axis")
+		return
+	}
+	if !mm.isValidValue(angle) || angle == 0 || 360+gog.Eps3D < math.Abs(angle) {
+		logger.Printf("Revolve: not valid angle: %v", angle)
+		return
+	}
+	full := 360-gog.Eps3D < math.Abs(angle)
+	if segments == 0 || full && segments < 3 {
+		logger.Printf("Revolve: not valid amount of segments: %d", segments)
+		return
+	}
+	// actions
+	defer mm.DeselectAll() // deselect
+	l := norm(dir)
+	for i := range dir {
+		dir[i] /= l
+	}
+	lines = uniqUint(append([]uint(nil), lines...))
+	var nodes []uint
+	for _, l := range lines {
+		for _, p := range mm.Elements[l].Indexes {
+			nodes = append(nodes, uint(p))
+		}
+	}
+	nodes = uniqUint(nodes)
+	onAxis := map[int]bool{}
+	for _, n := range nodes {
+		v := vector(origin, mm.Coords[n].Point3d)
+		onAxis[int(n)] = norm(cross(dir, v)) < gog.Eps3D
+	}
+	// nodes of layers
+	layers := []map[int]int{{}}
+	for _, n := range nodes {
+		layers[0][int(n)] = int(n)
+	}
+	for k := uint(1); k <= segments; k++ {
+		if full && k == segments {
+			layers = append(layers, layers[0])
+			break
+		}
+		layer := map[int]int{}
+		a := angle * radToDegree * float64(k) / float64(segments)
+		for _, n := range nodes {
+			if onAxis[int(n)] {
+				layer[int(n)] = int(n)
+				continue
+			}
+			p := rotate(mm.Coords[n].Point3d, origin, dir, a)
+			layer[int(n)] = int(mm.AddNode(p[0], p[1], p[2]))
+		}
+		layers = append(layers, layer)
+	}
+	// plates between layers
+	for k := 1; k < len(layers); k++ {
+		b, t := layers[k-1], layers[k]
+		for _, l := range lines {
+			idx := mm.Elements[l].Indexes
+			p0, p1 := idx[0], idx[1]
+			var (
+				id uint
+				ok bool
+			)
+			switch {
+			case onAxis[p0] && onAxis[p1]:
+				continue
+			case onAxis[p0]:
+				id, ok = mm.AddTriangle3ByNodeNumber(uint(p0), uint(b[p1]), uint(t[p1]))
+			case onAxis[p1]:
+				id, ok = mm.AddTriangle3ByNodeNumber(uint(b[p0]), uint(p1), uint(t[p0]))
+			default:
+				created = append(created, mm.addPlate([4]uint{
+					uint(b[p0]), uint(b[p1]),
+					uint(t[p1]), uint(t[p0]),
+				})...)
+				continue
+			}
+			if ok {
+				created = append(created, id)
+			}
+		}
+	}
+	return
+}
+
+// chainParameters return arc-length parameters of nodes of chain from 0
+// to 1
+func (mm *Model) chainParameters(chain []uint) (ps []float64) {
+	ps = make([]float64, len(chain))
+	for i := 1; i < len(chain); i++ {
+		ps[i] = ps[i-1] + gog.Distance3d(
+			mm.Coords[chain[i-1]].Point3d,
+			mm.Coords[chain[i]].Point3d,
+		)
+	}
+	if total := ps[len(ps)-1]; 0 < total {
+		for i := range ps {
+			ps[i] /= total
+		}
+	}
+	return
+}
+
+// chainPoint return point on chain for arc-length parameter
+func (mm *Model) chainPoint(chain []uint, ps []float64, u float64) gog.Point3d {
+	for i := 1; i < len(chain); i++ {
+		if u <= ps[i] || i == len(chain)-1 {
+			ratio := 0.0
+			if l := ps[i] - ps[i-1]; 0 < l {
+				ratio = (u - ps[i-1]) / l
+			}
+			return gog.PointLineRatio3d(
+				mm.Coords[chain[i-1]].Point3d,
+				mm.Coords[chain[i]].Point3d,
+				ratio,
+			)
+		}
+	}
+	return mm.Coords[chain[0]].Point3d
+}
+
+// Loft create plates between 2 chains of lines with amount of
+// intermediate sections. Points of chains are paired by arc-length
+// parameter. Quadr4 is created for paired points and Triangle3 for
+// other. Both chains must be closed or not closed.
+func (mm *Model) Loft(from, to []uint, sections uint) (created []uint) {
+	// check
+	isLine := func(t ElType) bool { return t == Line2 }
+	for _, s := range [][]uint{from, to} {
+		if !mm.isValidElementId(s, isLine) {
+			logger.Printf("Loft: not valid lines id: %v", s)
+			return
+		}
+	}
+	a, okA := mm.chainNodes(from)
+	b, okB := mm.chainNodes(to)
+	if !okA || !okB {
+		logger.Printf("Loft: lines is not chain")
+		return
+	}
+	closed := func(c []uint) bool { return c[0] == c[len(c)-1] }
+	if closed(a) != closed(b) {
+		logger.Printf("Loft: closed and not closed chains")
+		return
+	}
+	// actions
+	defer mm.DeselectAll() // deselect
+	point := func(n uint) gog.Point3d { return mm.Coords[n].Point3d }
+	reverse := func(c []uint) {
+		for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+			c[i], c[j] = c[j], c[i]
+		}
+	}
+	if closed(a) {
+		// same direction of loops
+		var pa, pb []gog.Point3d
+		for _, n := range a[1:] {
+			pa = append(pa, point(n))
+		}
+		for _, n := range b[1:] {
+			pb = append(pb, point(n))
+		}
+		if dot(plateNormal(pa), plateNormal(pb)) < 0 {
+			reverse(b)
+		}
+		// begin of second loop nearest to begin of first loop
+		begin := 0
+		for i := range b {
+			if gog.Distance3d(point(a[0]), point(b[i])) < gog.Distance3d(point(a[0]), point(b[begin])) {
+				begin = i
+			}
+		}
+		b = append(append([]uint(nil), b[begin:len(b)-1]...), b[:begin+1]...)
+	} else if gog.Distance3d(point(a[0]), point(b[len(b)-1]))+
+		gog.Distance3d(point(a[len(a)-1]), point(b[0])) <
+		gog.Distance3d(point(a[0]), point(b[0]))+
+			gog.Distance3d(point(a[len(a)-1]), point(b[len(b)-1])) {
+		reverse(b)
+	}
+	pa, pb := mm.chainParameters(a), mm.chainParameters(b)
+	// parameters of intermediate sections
+	us := append(append([]float64(nil), pa...), pb...)
+	sort.Float64s(us)
+	{
+		var uniq []float64
+		for _, u := range us {
+			if len(uniq) == 0 || 1e-9 < u-uniq[len(uniq)-1] {
+				uniq = append(uniq, u)
+			}
+		}
+		us = uniq
+	}
+	type layer struct {
+		nodes []uint
+		ps    []float64
+	}
+	layers := []layer{{a, pa}}
+	for k := uint(1); k <= sections; k++ {
+		t := float64(k) / float64(sections+1)
+		var l layer
+		for _, u := range us {
+			p0, p1 := mm.chainPoint(a, pa, u), mm.chainPoint(b, pb, u)
+			p := gog.PointLineRatio3d(p0, p1, t)
+			l.nodes = append(l.nodes, mm.AddNode(p[0], p[1], p[2]))
+			l.ps = append(l.ps, u)
+		}
+		layers = append(layers, l)
+	}
+	layers = append(layers, layer{b, pb})
+	// plates between layers
+	add := func(id uint, ok bool) {
+		if ok {
+			created = append(created, id)
+		}
+	}
+	for k := 1; k < len(layers); k++ {
+		la, lb := layers[k-1], layers[k]
+		for i, j := 0, 0; i < len(la.nodes)-1 || j < len(lb.nodes)-1; {
+			na, nb := math.Inf(1), math.Inf(1)
+			if i < len(la.nodes)-1 {
+				na = la.ps[i+1]
+			}
+			if j < len(lb.nodes)-1 {
+				nb = lb.ps[j+1]
+			}
+			switch {
+			case math.Abs(na-nb) < 1e-9:
+				created = append(created, mm.addPlate([4]uint{
+					la.nodes[i], la.nodes[i+1], lb.nodes[j+1], lb.nodes[j],
+				})...)
+				i, j = i+1, j+1
+			case na < nb:
+				add(mm.AddTriangle3ByNodeNumber(la.nodes[i], la.nodes[i+1], lb.nodes[j]))
+				i++
+			default:
+				add(mm.AddTriangle3ByNodeNumber(la.nodes[i], lb.nodes[j+1], lb.nodes[j]))
+				j++
+			}
+		}
+	}
+	return
+}
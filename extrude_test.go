@@ -0,0 +1,345 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Konstantin8105/gog"
+)
+
+func TestExtrude(t *testing.T) {
+	t.Run("lines", func(t *testing.T) {
+		var mm Model
+		var (
+			a = mm.AddNode(0, 0, 0)
+			b = mm.AddNode(1, 0, 0)
+			c = mm.AddNode(2, 0, 0)
+		)
+		l1 := mm.AddLineByNodeNumber(a, b)
+		l2 := mm.AddLineByNodeNumber(b, c)
+		qs := mm.Extrude([]uint{l1, l2}, [3]float64{0, 0, 2}, 4)
+		if len(qs) != 8 {
+			t.Fatalf("not valid amount of plates: %v", qs)
+		}
+		if len(mm.Coords) != 15 {
+			t.Errorf("nodes is not shared: %d", len(mm.Coords))
+		}
+		if area := mm.TotalArea(qs); math.Abs(area-4) > 1e-9 {
+			t.Errorf("not valid area: %v", area)
+		}
+	})
+	t.Run("quadrilaterals", func(t *testing.T) {
+		for _, dz := range []float64{-1, 1} {
+			var mm Model
+			qs := grid(&mm, 2)
+			hs := mm.Extrude(qs, [3]float64{0, 0, dz}, 2)
+			if len(hs) != 8 {
+				t.Fatalf("not valid amount of hexas: %v", hs)
+			}
+			if len(mm.Coords) != 27 {
+				t.Errorf("nodes is not shared: %d", len(mm.Coords))
+			}
+			for _, h := range hs {
+				if mm.Elements[h].ElementType != Hexa8 {
+					t.Fatalf("not valid type: %v", mm.Elements[h])
+				}
+				if v := hexaVolume(mm.getPoint3d(h)); math.Abs(v-0.5) > 1e-9 {
+					t.Errorf("not valid volume of %d: %v", h, v)
+				}
+			}
+		}
+	})
+	t.Run("path", func(t *testing.T) {
+		var mm Model
+		l := mm.AddLineByNodeNumber(mm.AddNode(0, 0, 0), mm.AddNode(1, 0, 0))
+		var (
+			p0 = mm.AddNode(0, 1, 1)
+			p1 = mm.AddNode(0, 0, 1)
+			p2 = mm.AddNode(0, 0, 0)
+		)
+		path := []uint{
+			mm.AddLineByNodeNumber(p0, p1),
+			mm.AddLineByNodeNumber(p2, p1),
+		}
+		qs := mm.ExtrudeByPath([]uint{l}, path, 2)
+		if len(qs) != 4 {
+			t.Fatalf("not valid amount of plates: %v", qs)
+		}
+		if area := mm.TotalArea(qs); math.Abs(area-2) > 1e-9 {
+			t.Errorf("not valid area: %v", area)
+		}
+		for _, n := range []uint{p0, p1} {
+			found := false
+			for _, q := range qs {
+				for _, p := range mm.Elements[q].Indexes {
+					found = found || p == int(n)
+				}
+			}
+			if !found {
+				t.Errorf("path node %d is not shared", n)
+			}
+		}
+	})
+}
+
+func TestRevolve(t *testing.T) {
+	var mm Model
+	var (
+		a0 = mm.AddNode(0, 0, 0)
+		a1 = mm.AddNode(0, 0, 1)
+		p0 = mm.AddNode(1, 0, 0)
+		p1 = mm.AddNode(1, 0, 2)
+	)
+	// bottom and wall of tank
+	lines := []uint{
+		mm.AddLineByNodeNumber(a0, p0),
+		mm.AddLineByNodeNumber(p0, p1),
+	}
+	els := mm.Revolve(lines, [2]uint{a0, a1}, 360, 8)
+	var amount [lastElement]int
+	for _, e := range els {
+		amount[mm.Elements[e].ElementType]++
+	}
+	if amount[Triangle3] != 8 || amount[Quadr4] != 8 {
+		t.Fatalf("not valid amount of elements: %v", amount)
+	}
+	// axis node, 2 nodes of profile and 2*7 rotated nodes
+	if len(mm.Coords) != 2+2+2*7 {
+		t.Errorf("nodes is duplicated: %d", len(mm.Coords))
+	}
+	side := math.Sin(math.Pi / 8)
+	if area := mm.TotalArea(els); math.Abs(area-(8*side*math.Cos(math.Pi/8)+16*side*2)) > 1e-9 {
+		t.Errorf("not valid area: %v", area)
+	}
+	for _, f := range mm.CheckAll() {
+		if f.Severity != SeverityInfo {
+			t.Errorf("%v", f)
+		}
+	}
+	if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 {
+		t.Errorf("not valid plates: %v", fs)
+	}
+	// half of revolution
+	var half Model
+	l := half.AddLineByNodeNumber(half.AddNode(1, 0, 0), half.AddNode(1, 0, 1))
+	ax := [2]uint{half.AddNode(0, 0, 0), half.AddNode(0, 0, 1)}
+	if els := half.Revolve([]uint{l}, ax, 180, 4); len(els) != 4 {
+		t.Errorf("not valid amount of elements: %v", els)
+	}
+	if c := half.Coords[len(half.Coords)-1].Point3d; math.Abs(c[0]+1) > 1e-9 || math.Abs(c[1]) > 1e-9 {
+		t.Errorf("not valid last node: %v", c)
+	}
+	// line out of meridian plane
+	var skew Model
+	l = skew.AddLineByNodeNumber(skew.AddNode(1, 0, 0), skew.AddNode(0, 1, 1))
+	ax = [2]uint{skew.AddNode(0, 0, 0), skew.AddNode(0, 0, 1)}
+	if els := skew.Revolve([]uint{l}, ax, 360, 8); len(els) != 16 {
+		t.Errorf("not valid amount of elements: %v", els)
+	}
+	for _, f := range skew.CheckAll() {
+		if f.Severity != SeverityInfo {
+			t.Errorf("%v", f)
+		}
+	}
+	// full revolution with not enough segments
+	for _, segments := range []uint{1, 2} {
+		if els := skew.Revolve([]uint{l}, ax, 360, segments); len(els) != 0 {
+			t.Errorf("not valid amount of segments %d: %v", segments, els)
+		}
+	}
+	// more than full revolution
+	for _, angle := range []float64{400, -720} {
+		if els := skew.Revolve([]uint{l}, ax, angle, 8); len(els) != 0 {
+			t.Errorf("not valid angle %v: %v", angle, els)
+		}
+	}
+}
+
+func TestMirrorHexa8(t *testing.T) {
+	plane := [3]gog.Point3d{{2, 0, 0}, {2, 1, 0}, {2, 0, 1}}
+	for _, copyModel := range []bool{false, true} {
+		t.Run(fmt.Sprint(copyModel), func(t *testing.T) {
+			var mm Model
+			hs := mm.Extrude(grid(&mm, 1), [3]float64{0, 0, 1}, 1)
+			mm.Mirror(nil, hs, plane, copyModel, false, false)
+			amount := 0
+			for i, el := range mm.Elements {
+				if el.ElementType != Hexa8 {
+					continue
+				}
+				amount++
+				if v := hexaVolume(mm.getPoint3d(uint(i))); math.Abs(v-1) > 1e-9 {
+					t.Errorf("not valid volume of %d: %v", i, v)
+				}
+			}
+			if exp := map[bool]int{false: 1, true: 2}[copyModel]; amount != exp {
+				t.Errorf("not valid amount of hexas: %d", amount)
+			}
+		})
+	}
+}
+
+func TestCopyLayer(t *testing.T) {
+	amount := func(mm *Model) (amount [lastElement]int) {
+		for _, el := range mm.Elements {
+			amount[el.ElementType]++
+		}
+		return
+	}
+	t.Run("copy", func(t *testing.T) {
+		var mm Model
+		l := mm.AddLineByNodeNumber(mm.AddNode(0, 0, 0), mm.AddNode(1, 0, 0))
+		step := DiffCoordinate{0, 1, 0, 0, 0, 0}
+		mm.Copy(nil, []uint{l}, [3]float64{}, []DiffCoordinate{step, step}, true, true)
+		if n := amount(&mm); n[Line2] != 7 || n[Triangle3] != 4 || len(mm.Coords) != 6 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		var all []uint
+		for i := range mm.Elements {
+			all = append(all, uint(i))
+		}
+		if a := mm.TotalArea(all); math.Abs(a-2) > 1e-9 {
+			t.Errorf("not valid area: %v", a)
+		}
+	})
+	t.Run("mirror", func(t *testing.T) {
+		var mm Model
+		l := mm.AddLineByNodeNumber(mm.AddNode(0, 0, 0), mm.AddNode(1, 0, 0))
+		plane := [3]gog.Point3d{{0, 1, 0}, {1, 1, 0}, {0, 1, 1}}
+		mm.Mirror(nil, []uint{l}, plane, true, true, true)
+		if n := amount(&mm); n[Line2] != 4 || n[Triangle3] != 2 || len(mm.Coords) != 4 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		// triangles by original and mirror nodes
+		var trs [][]int
+		for _, el := range mm.Elements {
+			if el.ElementType == Triangle3 {
+				trs = append(trs, el.Indexes)
+			}
+		}
+		if exp := [][]int{{0, 2, 1}, {1, 2, 3}}; fmt.Sprint(trs) != fmt.Sprint(exp) {
+			t.Errorf("not valid triangles: %v", trs)
+		}
+	})
+}
+
+func TestCopyByPath(t *testing.T) {
+	create := func() (mm *Model, line uint, path []uint) {
+		mm = new(Model)
+		line = mm.AddLineByNodeNumber(mm.AddNode(-0.5, 0, 0), mm.AddNode(0.5, 0, 0))
+		var (
+			p0 = mm.AddNode(0, 0, 0)
+			p1 = mm.AddNode(0, 0, 1)
+			p2 = mm.AddNode(1, 0, 1)
+		)
+		path = []uint{mm.AddLineByNodeNumber(p2, p1), mm.AddLineByNodeNumber(p1, p0)}
+		return
+	}
+	isNear := func(t *testing.T, mm *Model, n int, exp gog.Point3d) {
+		t.Helper()
+		if d := gog.Distance3d(mm.Coords[n].Point3d, exp); d > 1e-9 {
+			t.Errorf("node %d: %v != %v", n, mm.Coords[n].Point3d, exp)
+		}
+	}
+	last := func(mm *Model) (line Element) {
+		for _, el := range mm.Elements {
+			if el.ElementType == Line2 {
+				line = el
+			}
+		}
+		return
+	}
+	t.Run("copy", func(t *testing.T) {
+		mm, line, path := create()
+		mm.CopyByPath(nil, []uint{line}, path, false, true, false, true)
+		end := last(mm)
+		isNear(t, mm, end.Indexes[0], gog.Point3d{0.5, 0, 1})
+		isNear(t, mm, end.Indexes[1], gog.Point3d{1.5, 0, 1})
+		var trs []uint
+		for i, el := range mm.Elements {
+			if el.ElementType == Triangle3 {
+				trs = append(trs, uint(i))
+			}
+		}
+		if area := mm.TotalArea(trs); math.Abs(area-1) > 1e-9 {
+			t.Errorf("not valid area: %v", area)
+		}
+	})
+	t.Run("rotation", func(t *testing.T) {
+		mm, line, path := create()
+		mm.CopyByPath(nil, []uint{line}, path, true, true, true, false)
+		for _, p := range []gog.Point3d{{1, 0, 1.5}, {1, 0, 0.5}} {
+			found := false
+			for _, c := range mm.Coords {
+				found = found || gog.Distance3d(c.Point3d, p) < 1e-9
+			}
+			if !found {
+				t.Errorf("node is not found: %v", p)
+			}
+		}
+		// lines: profile, path, 2 copies, 2*2 connections
+		if n := len(mm.Elements); n != 1+2+2+4 {
+			t.Errorf("not valid amount of elements: %d", n)
+		}
+	})
+	t.Run("move", func(t *testing.T) {
+		mm, line, path := create()
+		mm.CopyByPath(nil, []uint{line}, path, false, false, false, false)
+		isNear(t, mm, mm.Elements[line].Indexes[0], gog.Point3d{0.5, 0, 1})
+		isNear(t, mm, mm.Elements[line].Indexes[1], gog.Point3d{1.5, 0, 1})
+	})
+}
+
+func TestLoft(t *testing.T) {
+	for _, tc := range []struct {
+		sections uint
+		amount   int
+	}{
+		{0, 8},
+		{1, 16},
+		{3, 32},
+	} {
+		t.Run(fmt.Sprintf("%d", tc.sections), func(t *testing.T) {
+			var mm Model
+			from := square(&mm, 0, 0, 2, 2)
+			begin := len(mm.Coords)
+			to := square(&mm, 0, 0, 1, 1)
+			for i := begin; i < len(mm.Coords); i++ {
+				mm.Coords[i].Point3d[2] = 1
+			}
+			ps := mm.Loft(from, to, tc.sections)
+			if len(ps) != tc.amount {
+				t.Fatalf("not valid amount of plates: %d", len(ps))
+			}
+			// 4 trapezoids with bases 2 and 1 and height sqrt(1.25)
+			expect := 4 * 1.5 * math.Sqrt(1.25)
+			if area := mm.TotalArea(ps); math.Abs(area-expect) > 1e-9 {
+				t.Errorf("not valid area: %v != %v", area, expect)
+			}
+			// same orientation of all plates
+			var inside int
+			for _, p := range ps {
+				ps := mm.getPoint3d(p)
+				if dot(plateNormal(ps), vector(gog.Point3d{0, 0, 0.5}, centroid(ps))) < 0 {
+					inside++
+				}
+			}
+			if inside != 0 && inside != len(ps) {
+				t.Errorf("not same orientation of plates: %d", inside)
+			}
+		})
+	}
+	t.Run("not valid", func(t *testing.T) {
+		var mm Model
+		from := square(&mm, 0, 0, 2, 2)
+		var (
+			a = mm.AddNode(0, 0, 1)
+			b = mm.AddNode(1, 0, 1)
+		)
+		to := []uint{mm.AddLineByNodeNumber(a, b)}
+		if ps := mm.Loft(from, to, 2); len(ps) != 0 {
+			t.Errorf("closed and not closed chains: %v", ps)
+		}
+	})
+}
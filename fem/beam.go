@@ -0,0 +1,314 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+)
+
+// Beam is 3D Euler-Bernoulli beam element with 6 degrees of freedom
+// in each node.
+//
+// Local axe x is from first to second node. Local axe z is in plane
+// of local axe x and global axe Z, for vertical beam local axe z is in
+// plane of local axe x and global axe X.
+type Beam struct {
+	Nodes [2]int
+
+	E, G float64 // elastic and shear modulus
+
+	A      float64 // area of cross-section
+	Iy, Iz float64 // moment of inertia around local axes
+	J      float64 // torsion moment of inertia
+
+	Density float64 // mass per volume
+
+	// Release is released directions at begin and end of beam
+	// in local coordinates
+	Release [2][6]bool
+
+	// Load is uniform distributed load per length in global coordinates
+	Load [3]float64
+}
+
+// axes return local axes of beam and length
+func (b Beam) axes(nodes [][3]float64) (r [3][3]float64, L float64, err error) {
+	p1, p2 := nodes[b.Nodes[0]], nodes[b.Nodes[1]]
+	var x [3]float64
+	for i := range x {
+		x[i] = p2[i] - p1[i]
+	}
+	L = norm(x)
+	if L == 0 {
+		err = fmt.Errorf("zero length")
+		return
+	}
+	x = scale(x, 1/L)
+	ref := [3]float64{0, 0, 1}
+	if 0.999 < math.Abs(x[2]) {
+		ref = [3]float64{1, 0, 0}
+	}
+	// z = ref - (ref.x) x
+	z := ref
+	dot := dot(ref, x)
+	for i := range z {
+		z[i] -= dot * x[i]
+	}
+	z = scale(z, 1/norm(z))
+	y := cross(z, x)
+	r = [3][3]float64{x, y, z}
+	return
+}
+
+// local return stiffness matrix and equivalent nodal loads in local
+// coordinates without releases
+func (b Beam) local(r [3][3]float64, L float64) (k [][]float64, f []float64) {
+	k = matrix(12)
+	set := func(i, j int, v float64) {
+		k[i][j] = v
+		k[j][i] = v
+	}
+	ea := b.E * b.A / L
+	set(0, 0, ea)
+	set(6, 6, ea)
+	set(0, 6, -ea)
+	gj := b.G * b.J / L
+	set(3, 3, gj)
+	set(9, 9, gj)
+	set(3, 9, -gj)
+	// bending in local plane xy
+	ei := b.E * b.Iz
+	set(1, 1, 12*ei/(L*L*L))
+	set(1, 5, 6*ei/(L*L))
+	set(1, 7, -12*ei/(L*L*L))
+	set(1, 11, 6*ei/(L*L))
+	set(5, 5, 4*ei/L)
+	set(5, 7, -6*ei/(L*L))
+	set(5, 11, 2*ei/L)
+	set(7, 7, 12*ei/(L*L*L))
+	set(7, 11, -6*ei/(L*L))
+	set(11, 11, 4*ei/L)
+	// bending in local plane xz
+	ei = b.E * b.Iy
+	set(2, 2, 12*ei/(L*L*L))
+	set(2, 4, -6*ei/(L*L))
+	set(2, 8, -12*ei/(L*L*L))
+	set(2, 10, -6*ei/(L*L))
+	set(4, 4, 4*ei/L)
+	set(4, 8, 6*ei/(L*L))
+	set(4, 10, 2*ei/L)
+	set(8, 8, 12*ei/(L*L*L))
+	set(8, 10, 6*ei/(L*L))
+	set(10, 10, 4*ei/L)
+
+	// equivalent nodal loads
+	f = make([]float64, 12)
+	var q [3]float64
+	for i := range q {
+		q[i] = dot(r[i], b.Load)
+	}
+	f[0] = q[0] * L / 2
+	f[6] = q[0] * L / 2
+	f[1] = q[1] * L / 2
+	f[5] = q[1] * L * L / 12
+	f[7] = q[1] * L / 2
+	f[11] = -q[1] * L * L / 12
+	f[2] = q[2] * L / 2
+	f[4] = -q[2] * L * L / 12
+	f[8] = q[2] * L / 2
+	f[10] = q[2] * L * L / 12
+	return
+}
+
+// condensation of released directions. Return transformation from
+// not released directions to all directions: u = T u'.
+func (b Beam) condensation(k [][]float64, f []float64) (t [][]float64) {
+	t = matrix(12)
+	for i := range t {
+		t[i][i] = 1
+	}
+	for end := range b.Release {
+		for d := range b.Release[end] {
+			if !b.Release[end][d] {
+				continue
+			}
+			p := 6*end + d
+			kpp := k[p][p]
+			// released direction by other directions
+			s := make([]float64, 12)
+			if kpp != 0 {
+				for j := range s {
+					if j != p {
+						s[j] = -k[p][j] / kpp
+					}
+				}
+			}
+			for i := range t {
+				tp := t[i][p]
+				if tp == 0 {
+					continue
+				}
+				t[i][p] = 0
+				for j := range s {
+					t[i][j] += tp * s[j]
+				}
+			}
+			if kpp != 0 {
+				for i := range k {
+					if i == p {
+						continue
+					}
+					c := k[i][p] / kpp
+					for j := range k {
+						if j == p {
+							continue
+						}
+						k[i][j] -= c * k[p][j]
+					}
+					f[i] -= c * f[p]
+				}
+			}
+			for i := range k {
+				k[i][p] = 0
+				k[p][i] = 0
+			}
+			f[p] = 0
+		}
+	}
+	return
+}
+
+// transformation matrix from global to local coordinates
+func transformation(r [3][3]float64, nodes int) (t [][]float64) {
+	t = matrix(6 * nodes)
+	for b := 0; b < 2*nodes; b++ {
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				t[3*b+i][3*b+j] = r[i][j]
+			}
+		}
+	}
+	return
+}
+
+// stiffness return stiffness matrix and equivalent nodal loads
+// in global coordinates
+func (b Beam) stiffness(nodes [][3]float64) (k [][]float64, f []float64, err error) {
+	r, L, err := b.axes(nodes)
+	if err != nil {
+		return
+	}
+	kl, fl := b.local(r, L)
+	b.condensation(kl, fl)
+	t := transformation(r, 2)
+	k = tmt(t, kl)
+	f = tmv(t, fl)
+	return
+}
+
+// forces return forces at ends of beam in local coordinates
+// for global displacements of beam nodes
+func (b Beam) forces(nodes [][3]float64, u []float64) (fs [12]float64, err error) {
+	r, L, err := b.axes(nodes)
+	if err != nil {
+		return
+	}
+	kl, fl := b.local(r, L)
+	b.condensation(kl, fl)
+	t := transformation(r, 2)
+	ul := mv(t, u)
+	kul := mv(kl, ul)
+	for i := range fs {
+		fs[i] = kul[i] - fl[i]
+	}
+	return
+}
+
+func (b Beam) dofs() (g []int) {
+	for _, n := range b.Nodes {
+		for d := 0; d < 6; d++ {
+			g = append(g, 6*n+d)
+		}
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+func matrix(n int) (m [][]float64) {
+	m = make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	return
+}
+
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func scale(a [3]float64, f float64) [3]float64 {
+	return [3]float64{a[0] * f, a[1] * f, a[2] * f}
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// mv return matrix-vector product
+func mv(m [][]float64, v []float64) (r []float64) {
+	r = make([]float64, len(m))
+	for i := range m {
+		for j := range m[i] {
+			r[i] += m[i][j] * v[j]
+		}
+	}
+	return
+}
+
+// tmv return Tt * v
+func tmv(t [][]float64, v []float64) (r []float64) {
+	r = make([]float64, len(t[0]))
+	for i := range t {
+		for j := range t[i] {
+			r[j] += t[i][j] * v[i]
+		}
+	}
+	return
+}
+
+// tmt return Tt * M * T
+func tmt(t, m [][]float64) (r [][]float64) {
+	n := len(t[0])
+	mt := make([][]float64, len(m))
+	for i := range m {
+		mt[i] = make([]float64, n)
+		for k := range m[i] {
+			if m[i][k] == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				mt[i][j] += m[i][k] * t[k][j]
+			}
+		}
+	}
+	r = matrix(n)
+	for k := range t {
+		for i := 0; i < n; i++ {
+			if t[k][i] == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				r[i][j] += t[k][i] * mt[k][j]
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,301 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// geometric return geometric stiffness matrix of beam in global
+// coordinates for axial force, tension is positive
+func (b Beam) geometric(nodes [][3]float64, N float64) (k [][]float64, err error) {
+	r, L, err := b.axes(nodes)
+	if err != nil {
+		return
+	}
+	kg := matrix(12)
+	set := func(i, j int, v float64) {
+		kg[i][j] = v
+		kg[j][i] = v
+	}
+	c := N / L
+	// bending in local plane xy
+	set(1, 1, 6./5*c)
+	set(1, 5, L/10*c)
+	set(1, 7, -6./5*c)
+	set(1, 11, L/10*c)
+	set(5, 5, 2*L*L/15*c)
+	set(5, 7, -L/10*c)
+	set(5, 11, -L*L/30*c)
+	set(7, 7, 6./5*c)
+	set(7, 11, -L/10*c)
+	set(11, 11, 2*L*L/15*c)
+	// bending in local plane xz
+	set(2, 2, 6./5*c)
+	set(2, 4, -L/10*c)
+	set(2, 8, -6./5*c)
+	set(2, 10, -L/10*c)
+	set(4, 4, 2*L*L/15*c)
+	set(4, 8, L/10*c)
+	set(4, 10, -L*L/30*c)
+	set(8, 8, 6./5*c)
+	set(8, 10, L/10*c)
+	set(10, 10, 2*L*L/15*c)
+	// torsion
+	if b.A != 0 {
+		ct := c * (b.Iy + b.Iz) / b.A
+		set(3, 3, ct)
+		set(3, 9, -ct)
+		set(9, 9, ct)
+	}
+	kl, fl := b.local(r, L)
+	kg = tmt(b.condensation(kl, fl), kg)
+	k = tmt(transformation(r, 2), kg)
+	return
+}
+
+// geometric return geometric stiffness matrix of plate in global
+// coordinates for membrane forces Nx, Ny, Nxy in local coordinates.
+// Matrix is for translations by linear shape functions of membrane.
+func (p Plate) geometric(nodes [][3]float64, nf [3]float64) (k [][]float64, err error) {
+	_, xy, err := p.axes(nodes)
+	if err != nil {
+		return
+	}
+	n := len(xy)
+	var pps []platePoint
+	if n == 3 {
+		pps, _ = triangle(xy, [][2]float64{{1. / 3, 1. / 3}})
+	} else {
+		pps, err = quadrilateral(xy, gauss2x2, 1)
+		if err != nil {
+			return
+		}
+	}
+	// kk[i][j] is integral of gradient(Ni) * N * gradient(Nj) by area
+	kk := matrix(n)
+	for _, pp := range pps {
+		for i := 0; i < n; i++ {
+			dxi, dyi := pp.bm[0][2*i], pp.bm[1][2*i+1]
+			for j := 0; j < n; j++ {
+				dxj, dyj := pp.bm[0][2*j], pp.bm[1][2*j+1]
+				kk[i][j] += (dxi*(nf[0]*dxj+nf[2]*dyj) + dyi*(nf[2]*dxj+nf[1]*dyj)) * pp.dA
+			}
+		}
+	}
+	k = matrix(6 * n)
+	for i := range kk {
+		for j := range kk[i] {
+			for d := 0; d < 3; d++ {
+				k[6*i+d][6*j+d] = kk[i][j]
+			}
+		}
+	}
+	return
+}
+
+// Buckling is result of linear buckling analysis
+type Buckling struct {
+	// Factors is critical load factors in ascending order
+	Factors []float64
+	// Shapes of buckling modes with maximal translation equal 1
+	Shapes [][][6]float64
+}
+
+// Buckling return lowest positive critical load factors of loads in
+// model. Internal forces is result of linear static analysis.
+func (m Model) Buckling(modes int) (bk Buckling, err error) {
+	if modes <= 0 {
+		err = fmt.Errorf("not valid amount of modes: %d", modes)
+		return
+	}
+	st, err := m.LinearStatic()
+	if err != nil {
+		return
+	}
+	s, err := m.stiffness(false)
+	if err != nil {
+		return
+	}
+	for i := range s.d.u0 {
+		s.d.u0[i] = 0
+	}
+	g := newSystem(s.d)
+	for i, b := range m.Beams {
+		f := st.BeamForces[i]
+		kg, err := b.geometric(m.Nodes, (f[6]-f[0])/2)
+		if err != nil {
+			return bk, fmt.Errorf("beam %d: %v", i, err)
+		}
+		g.add(b.dofs(), kg, nil)
+	}
+	for i, p := range m.Plates {
+		var nf [3]float64
+		for _, f := range st.PlateForces[i] {
+			for k := range nf {
+				nf[k] += f[k] / float64(len(p.Nodes))
+			}
+		}
+		kg, err := p.geometric(m.Nodes, nf)
+		if err != nil {
+			return bk, fmt.Errorf("plate %d: %v", i, err)
+		}
+		g.add(p.dofs(), kg, nil)
+	}
+	factors, vectors, err := critical(s.k, g.k, modes)
+	if err != nil {
+		return
+	}
+	bk.Factors = factors
+	for _, v := range vectors {
+		u := s.displacements(v)
+		var umax float64
+		for p := range m.Nodes {
+			for d := 0; d < 3; d++ {
+				umax = math.Max(umax, math.Abs(u[6*p+d]))
+			}
+		}
+		if umax == 0 {
+			// buckling by rotations only
+			for _, v := range u {
+				umax = math.Max(umax, math.Abs(v))
+			}
+		}
+		shape := make([][6]float64, len(m.Nodes))
+		for p := range shape {
+			for d := 0; d < 6; d++ {
+				shape[p][d] = u[6*p+d] / umax
+			}
+		}
+		bk.Shapes = append(bk.Shapes, shape)
+	}
+	return
+}
+
+// critical return lowest positive eigenvalues and eigenvectors of
+// problem (K + λ G) x = 0 by subspace iteration for problem
+// -G x = κ K x with largest κ = 1/λ.
+func critical(k, g *sparse, modes int) (values []float64, vectors [][]float64, err error) {
+	sk, err := k.factorize()
+	if err != nil {
+		return
+	}
+	if sk.negatives() != 0 {
+		err = fmt.Errorf("stiffness matrix is not positive definite")
+		return
+	}
+	modes = min(modes, k.n)
+	q := min(2*modes, modes+8, k.n)
+	neg := func(x []float64) (y []float64) {
+		y = g.mul(x)
+		for i := range y {
+			y[i] = -y[i]
+		}
+		return
+	}
+	rnd := rand.New(rand.NewSource(1))
+	x := make([][]float64, q)
+	for v := range x {
+		x[v] = make([]float64, k.n)
+		for i := range x[v] {
+			x[v][i] = rnd.Float64() - 0.5
+		}
+	}
+	const (
+		tolerance  = 1e-8
+		iterations = 200
+	)
+	var kappa, previous []float64
+	for iter := 0; ; iter++ {
+		if iterations < iter {
+			err = fmt.Errorf("critical load factors are not converged")
+			return
+		}
+		// inverse iteration
+		for v := range x {
+			x[v] = sk.solve(neg(x[v]))
+		}
+		// projection to subspace
+		gq, kq := matrix(q), matrix(q)
+		gx, kx := make([][]float64, q), make([][]float64, q)
+		for v := range x {
+			gx[v] = neg(x[v])
+			kx[v] = k.mul(x[v])
+		}
+		for a := 0; a < q; a++ {
+			for b := 0; b < q; b++ {
+				for i := 0; i < k.n; i++ {
+					gq[a][b] += x[a][i] * gx[b][i]
+					kq[a][b] += x[a][i] * kx[b][i]
+				}
+			}
+		}
+		var vs [][]float64
+		kappa, vs, err = generalized(gq, kq)
+		if err != nil {
+			return
+		}
+		// descending order of κ
+		for i, j := 0, q-1; i < j; i, j = i+1, j-1 {
+			kappa[i], kappa[j] = kappa[j], kappa[i]
+			for r := range vs {
+				vs[r][i], vs[r][j] = vs[r][j], vs[r][i]
+			}
+		}
+		nx := make([][]float64, q)
+		for v := range nx {
+			nx[v] = make([]float64, k.n)
+			for a := range x {
+				c := vs[a][v]
+				if c == 0 {
+					continue
+				}
+				for i := range nx[v] {
+					nx[v][i] += c * x[a][i]
+				}
+			}
+		}
+		x = nx
+		converged := previous != nil
+		for i := 0; i < modes && converged; i++ {
+			if tolerance*math.Abs(kappa[0]) < math.Abs(kappa[i]-previous[i]) {
+				converged = false
+			}
+		}
+		if converged {
+			break
+		}
+		previous = kappa
+	}
+	for i := 0; i < modes; i++ {
+		if kappa[i] <= 1e-12*math.Abs(kappa[0]) {
+			break
+		}
+		values = append(values, 1/kappa[i])
+		vectors = append(vectors, x[i])
+	}
+	if len(values) == 0 {
+		err = fmt.Errorf("structure without compression")
+		return
+	}
+	// Sturm sequence check
+	shift := values[len(values)-1] * (1 + 1e-6)
+	ks := newSparse(k.n)
+	for i := range k.rows {
+		for j, v := range k.rows[i] {
+			ks.add(i, j, v)
+		}
+		for j, v := range g.rows[i] {
+			ks.add(i, j, shift*v)
+		}
+	}
+	sks, e := ks.factorize()
+	if e != nil {
+		// shift is eigenvalue
+		return
+	}
+	if n := sks.negatives(); len(values) < n {
+		err = fmt.Errorf("missed %d critical load factors", n-len(values))
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBucklingColumn(t *testing.T) {
+	const L, P = 4.0, 1000.0
+	for _, tc := range []struct {
+		name  string
+		base  Support
+		top   [6]bool
+		ratio float64 // effective length ratio
+	}{
+		{"cantilever", Support{Node: 0, Fixed: fixed}, [6]bool{}, 2},
+		{"pinned", Support{Node: 0, Fixed: [6]bool{true, true, true, true, false, false}},
+			[6]bool{false, true, true, false, false, false}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var m Model
+			m.Nodes, m.Beams = beams(L, 16)
+			m.Supports = []Support{tc.base, {Node: 16, Fixed: tc.top}}
+			m.Loads = []Load{{Node: 16, Forces: [6]float64{-P, 0, 0, 0, 0, 0}}}
+			bk, err := m.Buckling(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			euler := func(I float64) float64 {
+				return math.Pi * math.Pi * E * I / math.Pow(tc.ratio*L, 2) / P
+			}
+			isNear(t, "factor 1", bk.Factors[0], euler(Iz), 1e-3)
+			isNear(t, "factor 2", bk.Factors[1], euler(Iy), 1e-3)
+			// maximal translation of shape
+			var umax float64
+			for _, d := range bk.Shapes[0] {
+				umax = math.Max(umax, math.Abs(d[1]))
+			}
+			isNear(t, "shape", umax, 1, 1e-9)
+		})
+	}
+}
+
+func TestBucklingPlate(t *testing.T) {
+	// simply supported square plate with uniform compression along X
+	const (
+		a, th, nu = 1.0, 0.01, 0.3
+		n         = 12
+		q         = 1000.0
+	)
+	D := E * th * th * th / (12 * (1 - nu*nu))
+	exp := 4 * math.Pi * math.Pi * D / (a * a) / q
+	for _, triangles := range []bool{false, true} {
+		var m Model
+		m.Nodes, m.Plates = square(a, n, triangles)
+		for i := range m.Plates {
+			m.Plates[i].E = E
+			m.Plates[i].Nu = nu
+			m.Plates[i].Thickness = th
+		}
+		for i, c := range m.Nodes {
+			s := Support{Node: i}
+			s.Fixed[5] = true
+			if c[0] == 0 || c[0] == a || c[1] == 0 || c[1] == a {
+				s.Fixed[2] = true
+			}
+			if c[0] == 0 {
+				s.Fixed[0] = true
+			}
+			if c[1] == 0 && c[0] == 0 {
+				s.Fixed[1] = true
+			}
+			if c[0] == a {
+				f := q * a / n
+				if c[1] == 0 || c[1] == a {
+					f /= 2
+				}
+				m.Loads = append(m.Loads, Load{Node: i, Forces: [6]float64{-f, 0, 0, 0, 0, 0}})
+			}
+			m.Supports = append(m.Supports, s)
+		}
+		bk, err := m.Buckling(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isNear(t, fmt.Sprintf("plate %v", triangles), bk.Factors[0], exp, 0.05)
+	}
+}
+
+func TestBucklingTension(t *testing.T) {
+	var m Model
+	m.Nodes, m.Beams = beams(2, 2)
+	m.Supports = []Support{{Node: 0, Fixed: fixed}}
+	m.Loads = []Load{{Node: 2, Forces: [6]float64{1000, 0, 0, 0, 0, 0}}}
+	if _, err := m.Buckling(1); err == nil {
+		t.Fatalf("buckling in tension")
+	}
+}
@@ -0,0 +1,253 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// subspace return lowest eigenvalues and eigenvectors of generalized
+// problem K x = λ M x by subspace iteration. Eigenvectors are normalized
+// by matrix M. Amount of found eigenvalues is checked by Sturm sequence.
+func subspace(k, m *sparse, modes int) (values []float64, vectors [][]float64, err error) {
+	sk, err := k.factorize()
+	if err != nil {
+		return
+	}
+	// directions with mass
+	var massive []int
+	for i := 0; i < m.n; i++ {
+		if 0 < m.get(i, i) {
+			massive = append(massive, i)
+		}
+	}
+	if len(massive) == 0 {
+		err = fmt.Errorf("structure without mass")
+		return
+	}
+	modes = min(modes, len(massive))
+	q := min(2*modes, modes+8, len(massive))
+	// start vectors: diagonal of mass and random vectors in directions
+	// with mass. Random generator with constant seed for repeatable
+	// results.
+	rnd := rand.New(rand.NewSource(1))
+	x := make([][]float64, q)
+	x[0] = make([]float64, m.n)
+	for i := range x[0] {
+		x[0][i] = m.get(i, i)
+	}
+	for v := 1; v < q; v++ {
+		x[v] = make([]float64, m.n)
+		for _, i := range massive {
+			x[v][i] = rnd.Float64() - 0.5
+		}
+	}
+	const (
+		tolerance  = 1e-8
+		iterations = 200
+	)
+	var previous []float64
+	for iter := 0; ; iter++ {
+		if iterations < iter {
+			err = fmt.Errorf("eigenvalues are not converged")
+			return
+		}
+		// inverse iteration
+		y := make([][]float64, q)
+		for v := range x {
+			y[v] = m.mul(x[v])
+			x[v] = sk.solve(y[v])
+		}
+		// projection to subspace
+		kq, mq := matrix(q), matrix(q)
+		my := make([][]float64, q)
+		for v := range x {
+			my[v] = m.mul(x[v])
+		}
+		for a := 0; a < q; a++ {
+			for b := 0; b < q; b++ {
+				for i := 0; i < m.n; i++ {
+					kq[a][b] += x[a][i] * y[b][i]
+					mq[a][b] += x[a][i] * my[b][i]
+				}
+			}
+		}
+		var vs [][]float64
+		values, vs, err = generalized(kq, mq)
+		if err != nil {
+			return
+		}
+		nx := make([][]float64, q)
+		for v := range nx {
+			nx[v] = make([]float64, m.n)
+			for a := range x {
+				c := vs[a][v]
+				if c == 0 {
+					continue
+				}
+				for i := range nx[v] {
+					nx[v][i] += c * x[a][i]
+				}
+			}
+		}
+		x = nx
+		converged := previous != nil
+		for i := 0; i < modes && converged; i++ {
+			if tolerance*math.Abs(values[i]) < math.Abs(values[i]-previous[i]) {
+				converged = false
+			}
+		}
+		if converged {
+			break
+		}
+		previous = values
+	}
+	values, vectors = values[:modes], x[:modes]
+	// Sturm sequence check
+	shift := values[modes-1] * (1 + 1e-6)
+	if shift <= 0 {
+		return
+	}
+	ks := newSparse(k.n)
+	for i := range k.rows {
+		for j, v := range k.rows[i] {
+			ks.add(i, j, v)
+		}
+		for j, v := range m.rows[i] {
+			ks.add(i, j, -shift*v)
+		}
+	}
+	sks, e := ks.factorize()
+	if e != nil {
+		// shift is eigenvalue
+		return
+	}
+	if n := sks.negatives(); modes < n {
+		err = fmt.Errorf("missed %d eigenvalues", n-modes)
+	}
+	return
+}
+
+// generalized return eigenvalues in ascending order and eigenvectors of
+// small dense problem K x = λ M x. Eigenvectors is columns of matrix and
+// normalized by matrix M.
+func generalized(k, m [][]float64) (values []float64, vectors [][]float64, err error) {
+	n := len(k)
+	// Cholesky M = L Lt
+	l := matrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := m[i][j]
+			for p := 0; p < j; p++ {
+				sum -= l[i][p] * l[j][p]
+			}
+			if i == j {
+				if sum <= 0 {
+					err = fmt.Errorf("mass matrix is not positive definite")
+					return
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	// inverse of L
+	li := matrix(n)
+	for j := 0; j < n; j++ {
+		li[j][j] = 1 / l[j][j]
+		for i := j + 1; i < n; i++ {
+			var sum float64
+			for p := j; p < i; p++ {
+				sum -= l[i][p] * li[p][j]
+			}
+			li[i][j] = sum / l[i][i]
+		}
+	}
+	// standard problem A = inv(L) K inv(Lt)
+	a := matrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for p := 0; p <= i; p++ {
+				for r := 0; r <= j; r++ {
+					a[i][j] += li[i][p] * k[p][r] * li[j][r]
+				}
+			}
+		}
+	}
+	ev, vs := jacobi(a)
+	// vectors of generalized problem x = inv(Lt) v
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(p, r int) bool { return ev[order[p]] < ev[order[r]] })
+	values = make([]float64, n)
+	vectors = matrix(n)
+	for c, o := range order {
+		values[c] = ev[o]
+		for i := 0; i < n; i++ {
+			for p := i; p < n; p++ {
+				vectors[i][c] += li[p][i] * vs[p][o]
+			}
+		}
+	}
+	return
+}
+
+// jacobi return eigenvalues and eigenvectors in columns of symmetric
+// matrix by Jacobi rotations
+func jacobi(a [][]float64) (values []float64, v [][]float64) {
+	n := len(a)
+	v = matrix(n)
+	for i := range v {
+		v[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off, diag float64
+		for i := 0; i < n; i++ {
+			diag += a[i][i] * a[i][i]
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off <= 1e-30*diag {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for r := 0; r < n; r++ {
+					arp, arq := a[r][p], a[r][q]
+					a[r][p] = c*arp - s*arq
+					a[r][q] = s*arp + c*arq
+				}
+				for r := 0; r < n; r++ {
+					apr, aqr := a[p][r], a[q][r]
+					a[p][r] = c*apr - s*aqr
+					a[q][r] = s*apr + c*aqr
+				}
+				for r := 0; r < n; r++ {
+					vrp, vrq := v[r][p], v[r][q]
+					v[r][p] = c*vrp - s*vrq
+					v[r][q] = s*vrp + c*vrq
+				}
+			}
+		}
+	}
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return
+}
@@ -0,0 +1,239 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mass is point mass in node for all translations
+type Mass struct {
+	Node int
+	Mass float64
+}
+
+// mass return mass matrix of beam in global coordinates. Lumped mass is
+// without rotation inertia.
+func (b Beam) mass(nodes [][3]float64, lumped bool) (m [][]float64, err error) {
+	r, L, err := b.axes(nodes)
+	if err != nil {
+		return
+	}
+	ml := matrix(12)
+	set := func(i, j int, v float64) {
+		ml[i][j] = v
+		ml[j][i] = v
+	}
+	ma := b.Density * b.A * L
+	if lumped {
+		for _, d := range []int{0, 1, 2, 6, 7, 8} {
+			ml[d][d] = ma / 2
+		}
+	} else {
+		c := ma / 420
+		set(0, 0, 140*c)
+		set(0, 6, 70*c)
+		set(6, 6, 140*c)
+		// torsion by polar moment of inertia
+		mt := b.Density * (b.Iy + b.Iz) * L
+		set(3, 3, mt/3)
+		set(3, 9, mt/6)
+		set(9, 9, mt/3)
+		// bending in local plane xy
+		set(1, 1, 156*c)
+		set(1, 5, 22*L*c)
+		set(1, 7, 54*c)
+		set(1, 11, -13*L*c)
+		set(5, 5, 4*L*L*c)
+		set(5, 7, 13*L*c)
+		set(5, 11, -3*L*L*c)
+		set(7, 7, 156*c)
+		set(7, 11, -22*L*c)
+		set(11, 11, 4*L*L*c)
+		// bending in local plane xz
+		set(2, 2, 156*c)
+		set(2, 4, -22*L*c)
+		set(2, 8, 54*c)
+		set(2, 10, 13*L*c)
+		set(4, 4, 4*L*L*c)
+		set(4, 8, -13*L*c)
+		set(4, 10, -3*L*L*c)
+		set(8, 8, 156*c)
+		set(8, 10, 22*L*c)
+		set(10, 10, 4*L*L*c)
+	}
+	kl, fl := b.local(r, L)
+	ml = tmt(b.condensation(kl, fl), ml)
+	m = tmt(transformation(r, 2), ml)
+	return
+}
+
+// mass return mass matrix of plate in global coordinates.
+// Mass matrix is for translations only, consistent mass is by linear
+// shape functions of membrane.
+func (p Plate) mass(nodes [][3]float64, lumped bool) (m [][]float64, err error) {
+	_, xy, err := p.axes(nodes)
+	if err != nil {
+		return
+	}
+	n := len(xy)
+	// mm[i][j] is integral of Ni * Nj by area
+	mm := matrix(n)
+	if n == 3 {
+		_, area := triangle(xy, nil)
+		for i := range mm {
+			for j := range mm[i] {
+				mm[i][j] = area / 12
+				if i == j {
+					mm[i][j] = area / 6
+				}
+			}
+		}
+	} else {
+		pps, err := quadrilateral(xy, gauss2x2, 1)
+		if err != nil {
+			return nil, err
+		}
+		for g, pp := range pps {
+			N := make([]float64, n)
+			for i := range N {
+				N[i] = (1 + quadrilateralNodes[i][0]*gauss2x2[g][0]) *
+					(1 + quadrilateralNodes[i][1]*gauss2x2[g][1]) / 4
+			}
+			for i := range mm {
+				for j := range mm[i] {
+					mm[i][j] += N[i] * N[j] * pp.dA
+				}
+			}
+		}
+	}
+	if lumped {
+		for i := range mm {
+			var sum float64
+			for j := range mm[i] {
+				sum += mm[i][j]
+				mm[i][j] = 0
+			}
+			mm[i][i] = sum
+		}
+	}
+	rt := p.Density * p.Thickness
+	m = matrix(6 * n)
+	for i := range mm {
+		for j := range mm[i] {
+			for d := 0; d < 3; d++ {
+				m[6*i+d][6*j+d] = rt * mm[i][j]
+			}
+		}
+	}
+	return
+}
+
+// mass return mass matrix of structure for equations of system
+func (m Model) mass(d dofs, lumped bool) (ms *sparse, err error) {
+	s := newSystem(d)
+	for i, b := range m.Beams {
+		me, err := b.mass(m.Nodes, lumped)
+		if err != nil {
+			return nil, fmt.Errorf("beam %d: %v", i, err)
+		}
+		s.add(b.dofs(), me, nil)
+	}
+	for i, p := range m.Plates {
+		me, err := p.mass(m.Nodes, lumped)
+		if err != nil {
+			return nil, fmt.Errorf("plate %d: %v", i, err)
+		}
+		s.add(p.dofs(), me, nil)
+	}
+	for i, pm := range m.Masses {
+		if pm.Node < 0 || len(m.Nodes) <= pm.Node {
+			return nil, fmt.Errorf("mass %d: not valid node %d", i, pm.Node)
+		}
+		for dir := 0; dir < 3; dir++ {
+			s.add([]int{6*pm.Node + dir}, [][]float64{{pm.Mass}}, nil)
+		}
+	}
+	ms = s.k
+	return
+}
+
+// Modal is result of modal analysis
+type Modal struct {
+	// Frequencies of modes in Hz
+	Frequencies []float64
+	// Shapes of modes normalized by mass matrix
+	Shapes [][][6]float64
+	// Participation factors of modes for global directions X, Y, Z
+	Participation [][3]float64
+	// EffectiveMass of modes for global directions X, Y, Z
+	EffectiveMass [][3]float64
+	// TotalMass of not fixed directions X, Y, Z
+	TotalMass [3]float64
+}
+
+// Modal return lowest modes of free vibration. Mass of structure is
+// consistent or lumped mass of elements with point masses.
+// Prescribed displacements of supports are zero and loads are ignored.
+func (m Model) Modal(modes int, lumped bool) (md Modal, err error) {
+	if modes <= 0 {
+		err = fmt.Errorf("not valid amount of modes: %d", modes)
+		return
+	}
+	s, err := m.stiffness(false)
+	if err != nil {
+		return
+	}
+	for i := range s.d.u0 {
+		s.d.u0[i] = 0
+	}
+	ms, err := m.mass(s.d, lumped)
+	if err != nil {
+		return
+	}
+	values, vectors, err := subspace(s.k, ms, modes)
+	if err != nil {
+		if e, ok := err.(singular); ok {
+			err = fmt.Errorf("structure is mechanism at %s", s.d.equation(e.eq))
+		}
+		return
+	}
+	// influence vectors of translations
+	var r [3][]float64
+	for dir := range r {
+		r[dir] = make([]float64, s.d.amount)
+	}
+	for dof := range s.d.terms {
+		if _, ok := s.d.slaves[dof]; ok || 2 < dof%6 {
+			continue
+		}
+		for _, t := range s.d.terms[dof] {
+			r[dof%6][t.eq] = t.c
+		}
+	}
+	var mr [3][]float64
+	for dir := range r {
+		mr[dir] = ms.mul(r[dir])
+		for i := range r[dir] {
+			md.TotalMass[dir] += r[dir][i] * mr[dir][i]
+		}
+	}
+	for i, v := range values {
+		md.Frequencies = append(md.Frequencies, math.Sqrt(math.Max(v, 0))/(2*math.Pi))
+		u := s.displacements(vectors[i])
+		shape := make([][6]float64, len(m.Nodes))
+		for p := range shape {
+			copy(shape[p][:], u[6*p:])
+		}
+		md.Shapes = append(md.Shapes, shape)
+		var pf, em [3]float64
+		for dir := range pf {
+			for k := range vectors[i] {
+				pf[dir] += vectors[i][k] * mr[dir][k]
+			}
+			em[dir] = pf[dir] * pf[dir]
+		}
+		md.Participation = append(md.Participation, pf)
+		md.EffectiveMass = append(md.EffectiveMass, em)
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func isNear(t *testing.T, name string, act, exp, tolerance float64) {
+	t.Helper()
+	if diff := math.Abs((act - exp) / exp); tolerance < diff {
+		t.Errorf("%s: actual %.6e, expected %.6e, diff %.3f%%", name, act, exp, diff*100)
+	}
+}
+
+const Density = 7850.0
+
+func TestModalCantilever(t *testing.T) {
+	const L = 3.0
+	for _, lumped := range []bool{false, true} {
+		t.Run(fmt.Sprint(lumped), func(t *testing.T) {
+			var m Model
+			m.Nodes, m.Beams = beams(L, 20)
+			for i := range m.Beams {
+				m.Beams[i].Density = Density
+			}
+			m.Supports = []Support{{Node: 0, Fixed: fixed}}
+			md, err := m.Modal(4, lumped)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := func(beta float64, I float64) float64 {
+				return beta * beta / (2 * math.Pi) * math.Sqrt(E*I/(Density*A*math.Pow(L, 4)))
+			}
+			// bending around Iz is lower
+			isNear(t, "mode 1", md.Frequencies[0], f(1.875104, Iz), 0.005)
+			isNear(t, "mode 2", md.Frequencies[1], f(1.875104, Iy), 0.005)
+			isNear(t, "mode 3", md.Frequencies[2], f(4.694091, Iz), 0.005)
+			isNear(t, "mode 4", md.Frequencies[3], f(4.694091, Iy), 0.005)
+			// effective mass of first mode of cantilever
+			isNear(t, "effective mass", md.EffectiveMass[0][1], 0.6131*Density*A*L, 0.01)
+			isNear(t, "effective mass", md.EffectiveMass[1][2], 0.6131*Density*A*L, 0.01)
+		})
+	}
+}
+
+func TestModalPointMass(t *testing.T) {
+	const L, M = 2.0, 500.0
+	var m Model
+	m.Nodes, m.Beams = beams(L, 2)
+	m.Supports = []Support{{Node: 0, Fixed: fixed}}
+	m.Masses = []Mass{{Node: 2, Mass: M}}
+	md, err := m.Modal(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isNear(t, "frequency", md.Frequencies[0], math.Sqrt(3*E*Iz/(L*L*L)/M)/(2*math.Pi), 1e-6)
+	isNear(t, "total mass", md.TotalMass[0], M, 1e-9)
+	isNear(t, "effective mass", md.EffectiveMass[0][1], M, 1e-6)
+	// mode shape is normalized by mass
+	isNear(t, "shape", math.Abs(md.Shapes[0][2][1]), 1/math.Sqrt(M), 1e-6)
+}
+
+func TestModalPlate(t *testing.T) {
+	// simply supported square plate
+	const (
+		a, th, nu = 1.0, 0.01, 0.3
+		n         = 12
+	)
+	D := E * th * th * th / (12 * (1 - nu*nu))
+	exp := math.Pi / (a * a) * math.Sqrt(D/(Density*th))
+	for _, triangles := range []bool{false, true} {
+		var m Model
+		m.Nodes, m.Plates = square(a, n, triangles)
+		for i := range m.Plates {
+			m.Plates[i].E = E
+			m.Plates[i].Nu = nu
+			m.Plates[i].Thickness = th
+			m.Plates[i].Density = Density
+		}
+		for i, c := range m.Nodes {
+			s := Support{Node: i}
+			s.Fixed[0], s.Fixed[1], s.Fixed[5] = true, true, true
+			if c[0] == 0 || c[0] == a || c[1] == 0 || c[1] == a {
+				s.Fixed[2] = true
+			}
+			m.Supports = append(m.Supports, s)
+		}
+		md, err := m.Modal(1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		isNear(t, fmt.Sprintf("plate %v", triangles), md.Frequencies[0], exp, 0.02)
+	}
+}
+
+func TestModalWithoutMass(t *testing.T) {
+	var m Model
+	m.Nodes, m.Beams = beams(2, 2)
+	m.Supports = []Support{{Node: 0, Fixed: fixed}}
+	if _, err := m.Modal(3, false); err == nil {
+		t.Fatalf("structure without mass")
+	}
+}
+
+func TestModalIgnoreLoads(t *testing.T) {
+	// pinned beams with moment in node without rotation stiffness
+	var m Model
+	m.Nodes = [][3]float64{{0, 0, 0}, {4, 0, 0}, {2, 0, 2}}
+	pinned := [6]bool{false, false, false, true, true, true}
+	for _, ns := range [][2]int{{0, 1}, {1, 2}, {2, 0}} {
+		m.Beams = append(m.Beams, Beam{
+			Nodes: ns,
+			E:     E, G: G, A: A, Iy: Iy, Iz: Iz, J: J,
+			Release: [2][6]bool{pinned, pinned},
+		})
+	}
+	m.Supports = []Support{
+		{Node: 0, Fixed: [6]bool{true, true, true, false, false, false}},
+		{Node: 1, Fixed: [6]bool{false, true, true, false, false, false}},
+		{Node: 2, Fixed: [6]bool{false, true, false, false, false, false}},
+	}
+	m.Masses = []Mass{{Node: 2, Mass: 100}}
+	m.Loads = []Load{{Node: 2, Forces: [6]float64{0, 0, 0, 0, 1000, 0}}}
+	if _, err := m.LinearStatic(); err == nil {
+		t.Fatalf("load in direction without stiffness")
+	}
+	md, err := m.Modal(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Frequencies) != 1 || !(0 < md.Frequencies[0]) {
+		t.Errorf("not valid frequencies: %v", md.Frequencies)
+	}
+}
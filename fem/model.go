@@ -0,0 +1,416 @@
+// Package fem is finite element analysis of structures with 6 degrees of
+// freedom in each node: Dx, Dy, Dz, Rx, Ry, Rz.
+package fem
+
+import (
+	"fmt"
+	"math"
+)
+
+// Support of node
+type Support struct {
+	Node int
+	// Fixed direction with prescribed displacement from Displacement
+	Fixed        [6]bool
+	Displacement [6]float64
+	// Stiffness of spring for not fixed direction
+	Stiffness [6]float64
+}
+
+// Link is rigid link between master node and slave nodes
+type Link struct {
+	Master    int
+	Slaves    []int
+	Direction [6]bool // linked directions
+}
+
+// Load is nodal load in global coordinates
+type Load struct {
+	Node   int
+	Forces [6]float64
+}
+
+// Model of structure
+type Model struct {
+	Nodes    [][3]float64
+	Beams    []Beam
+	Plates   []Plate
+	Supports []Support
+	Links    []Link
+	Loads    []Load
+	Masses   []Mass
+}
+
+// term of linear combination of equations
+type term struct {
+	eq int
+	c  float64
+}
+
+// dofs is map from degrees of freedom of nodes to equations
+// as linear combination of unknown and constant:
+//
+//	u[dof] = sum(c * q[eq]) + u0[dof]
+type dofs struct {
+	amount int // amount of equations
+	terms  [][]term
+	u0     []float64
+	// slave directions as linear combination of master directions
+	slaves map[int][]term
+}
+
+func (m Model) dofs() (d dofs, err error) {
+	n := 6 * len(m.Nodes)
+	d.terms = make([][]term, n)
+	d.u0 = make([]float64, n)
+	d.slaves = map[int][]term{}
+
+	// used nodes
+	used := make([]bool, len(m.Nodes))
+	valid := func(n int) bool {
+		return 0 <= n && n < len(m.Nodes)
+	}
+	for i, b := range m.Beams {
+		for _, p := range b.Nodes {
+			if !valid(p) {
+				return d, fmt.Errorf("beam %d: not valid node %d", i, p)
+			}
+			used[p] = true
+		}
+	}
+	for i, pl := range m.Plates {
+		for _, p := range pl.Nodes {
+			if !valid(p) {
+				return d, fmt.Errorf("plate %d: not valid node %d", i, p)
+			}
+			used[p] = true
+		}
+	}
+	// linked slave directions
+	for i, l := range m.Links {
+		if !valid(l.Master) {
+			return d, fmt.Errorf("link %d: not valid master node %d", i, l.Master)
+		}
+		for _, s := range l.Slaves {
+			if !valid(s) {
+				return d, fmt.Errorf("link %d: not valid slave node %d", i, s)
+			}
+			if s == l.Master {
+				return d, fmt.Errorf("link %d: node %d is master and slave", i, s)
+			}
+			r := [3]float64{}
+			for k := range r {
+				r[k] = m.Nodes[s][k] - m.Nodes[l.Master][k]
+			}
+			mo := 6 * l.Master
+			for dir := range l.Direction {
+				if !l.Direction[dir] {
+					continue
+				}
+				dof := 6*s + dir
+				if _, ok := d.slaves[dof]; ok {
+					return d, fmt.Errorf("link %d: node %d is linked twice", i, s)
+				}
+				ts := []term{{eq: mo + dir, c: 1}}
+				// translation from rotation of master: rotation x r
+				switch dir {
+				case 0:
+					ts = append(ts, term{mo + 4, r[2]}, term{mo + 5, -r[1]})
+				case 1:
+					ts = append(ts, term{mo + 5, r[0]}, term{mo + 3, -r[2]})
+				case 2:
+					ts = append(ts, term{mo + 3, r[1]}, term{mo + 4, -r[0]})
+				}
+				d.slaves[dof] = ts
+			}
+			used[s] = true
+		}
+		used[l.Master] = true
+	}
+	for dof := range d.slaves {
+		for _, t := range d.slaves[dof] {
+			if _, ok := d.slaves[t.eq]; ok {
+				return d, fmt.Errorf("node %d is master and slave", t.eq/6)
+			}
+		}
+	}
+	// supports
+	fixed := make([]bool, n)
+	for i, s := range m.Supports {
+		if !valid(s.Node) {
+			return d, fmt.Errorf("support %d: not valid node %d", i, s.Node)
+		}
+		for dir := range s.Fixed {
+			if !s.Fixed[dir] {
+				continue
+			}
+			dof := 6*s.Node + dir
+			if _, ok := d.slaves[dof]; ok {
+				return d, fmt.Errorf("support %d: node %d is slave of rigid link", i, s.Node)
+			}
+			fixed[dof] = true
+			d.u0[dof] = s.Displacement[dir]
+		}
+	}
+	// equations of independent directions
+	for p := range m.Nodes {
+		if !used[p] {
+			continue
+		}
+		for dir := 0; dir < 6; dir++ {
+			dof := 6*p + dir
+			if _, ok := d.slaves[dof]; ok || fixed[dof] {
+				continue
+			}
+			d.terms[dof] = []term{{eq: d.amount, c: 1}}
+			d.amount++
+		}
+	}
+	// equations of slave directions
+	for dof, ts := range d.slaves {
+		for _, t := range ts {
+			for _, mt := range d.terms[t.eq] {
+				d.terms[dof] = append(d.terms[dof], term{eq: mt.eq, c: t.c * mt.c})
+			}
+			d.u0[dof] += t.c * d.u0[t.eq]
+		}
+	}
+	return
+}
+
+// system of linear equations
+type system struct {
+	d dofs
+	k *sparse
+	f []float64
+}
+
+func newSystem(d dofs) *system {
+	return &system{d: d, k: newSparse(d.amount), f: make([]float64, d.amount)}
+}
+
+// add element matrix and load vector for degrees of freedom
+func (s *system) add(g []int, ke [][]float64, fe []float64) {
+	for a := range g {
+		for _, ta := range s.d.terms[g[a]] {
+			if fe != nil {
+				s.f[ta.eq] += ta.c * fe[a]
+			}
+			for b := range g {
+				v := ke[a][b]
+				if v == 0 {
+					continue
+				}
+				s.f[ta.eq] -= ta.c * v * s.d.u0[g[b]]
+				for _, tb := range s.d.terms[g[b]] {
+					s.k.add(ta.eq, tb.eq, ta.c*tb.c*v)
+				}
+			}
+		}
+	}
+}
+
+// displacements of nodes by solution of equations
+func (s *system) displacements(q []float64) (u []float64) {
+	u = make([]float64, len(s.d.u0))
+	copy(u, s.d.u0)
+	for dof := range s.d.terms {
+		for _, t := range s.d.terms[dof] {
+			u[dof] += t.c * q[t.eq]
+		}
+	}
+	return
+}
+
+// stiffness return system with stiffness matrix and loads. Loads of
+// nodes are ignored for eigen problems, if withLoads is false.
+func (m Model) stiffness(withLoads bool) (s *system, err error) {
+	d, err := m.dofs()
+	if err != nil {
+		return
+	}
+	s = newSystem(d)
+	for i, b := range m.Beams {
+		ke, fe, err := b.stiffness(m.Nodes)
+		if err != nil {
+			return nil, fmt.Errorf("beam %d: %v", i, err)
+		}
+		s.add(b.dofs(), ke, fe)
+	}
+	for i, p := range m.Plates {
+		ke, fe, err := p.stiffness(m.Nodes)
+		if err != nil {
+			return nil, fmt.Errorf("plate %d: %v", i, err)
+		}
+		s.add(p.dofs(), ke, fe)
+	}
+	for _, sp := range m.Supports {
+		for dir := range sp.Stiffness {
+			if sp.Fixed[dir] || sp.Stiffness[dir] == 0 {
+				continue
+			}
+			s.add([]int{6*sp.Node + dir}, [][]float64{{sp.Stiffness[dir]}}, nil)
+		}
+	}
+	for i, l := range m.Loads {
+		if !withLoads {
+			continue
+		}
+		if l.Node < 0 || len(m.Nodes) <= l.Node {
+			return nil, fmt.Errorf("load %d: not valid node %d", i, l.Node)
+		}
+		g := make([]int, 6)
+		for dir := range g {
+			g[dir] = 6*l.Node + dir
+		}
+		s.add(g, matrix(6), l.Forces[:])
+	}
+	// directions without stiffness, for example rotation of node
+	// between beams with releases
+	for i := 0; i < s.k.n; i++ {
+		if s.k.get(i, i) != 0 {
+			continue
+		}
+		if s.f[i] != 0 {
+			return nil, fmt.Errorf("load in direction without stiffness")
+		}
+		s.k.add(i, i, 1)
+	}
+	return
+}
+
+// equation return description of equation for error messages
+func (d dofs) equation(eq int) string {
+	names := [6]string{"Dx", "Dy", "Dz", "Rx", "Ry", "Rz"}
+	for dof := range d.terms {
+		if _, ok := d.slaves[dof]; ok {
+			continue
+		}
+		for _, t := range d.terms[dof] {
+			if t.eq == eq {
+				return fmt.Sprintf("node %d direction %s", dof/6, names[dof%6])
+			}
+		}
+	}
+	return fmt.Sprintf("equation %d", eq)
+}
+
+// Static is result of linear static analysis
+type Static struct {
+	// Displacements of nodes in global coordinates
+	Displacements [][6]float64
+	// Reactions of supports in global coordinates
+	Reactions [][6]float64
+	// BeamForces is forces at ends of beams in local coordinates
+	BeamForces [][12]float64
+	// PlateForces is forces in nodes of plates in local coordinates
+	// of plate: Nx, Ny, Nxy, Mx, My, Mxy, Qx, Qy
+	PlateForces [][][8]float64
+	// Stress is average von Mises stress on surfaces of plates in nodes
+	Stress []float64
+}
+
+// LinearStatic return result of linear static analysis
+func (m Model) LinearStatic() (st Static, err error) {
+	s, err := m.stiffness(true)
+	if err != nil {
+		return
+	}
+	sk, err := s.k.factorize()
+	if err != nil {
+		if e, ok := err.(singular); ok {
+			err = fmt.Errorf("structure is mechanism at %s", s.d.equation(e.eq))
+		}
+		return
+	}
+	q := sk.solve(s.f)
+	u := s.displacements(q)
+	for _, v := range u {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			err = fmt.Errorf("not valid displacements")
+			return
+		}
+	}
+	st.Displacements = make([][6]float64, len(m.Nodes))
+	for p := range st.Displacements {
+		copy(st.Displacements[p][:], u[6*p:])
+	}
+	// forces in beams
+	residual := make([]float64, len(u))
+	st.BeamForces = make([][12]float64, len(m.Beams))
+	for i, b := range m.Beams {
+		g := b.dofs()
+		ue := make([]float64, len(g))
+		for k := range g {
+			ue[k] = u[g[k]]
+		}
+		st.BeamForces[i], err = b.forces(m.Nodes, ue)
+		if err != nil {
+			err = fmt.Errorf("beam %d: %v", i, err)
+			return
+		}
+		r, _, _ := b.axes(m.Nodes)
+		fg := tmv(transformation(r, 2), st.BeamForces[i][:])
+		for k := range g {
+			residual[g[k]] += fg[k]
+		}
+	}
+	// forces in plates
+	st.PlateForces = make([][][8]float64, len(m.Plates))
+	st.Stress = make([]float64, len(m.Nodes))
+	amount := make([]int, len(m.Nodes))
+	for i, p := range m.Plates {
+		g := p.dofs()
+		ue := make([]float64, len(g))
+		for k := range g {
+			ue[k] = u[g[k]]
+		}
+		st.PlateForces[i], err = p.forces(m.Nodes, ue)
+		if err != nil {
+			err = fmt.Errorf("plate %d: %v", i, err)
+			return
+		}
+		for k, n := range p.Nodes {
+			st.Stress[n] += p.stress(st.PlateForces[i][k])
+			amount[n]++
+		}
+		ke, fe, _ := p.stiffness(m.Nodes)
+		fg := mv(ke, ue)
+		for k := range g {
+			residual[g[k]] += fg[k] - fe[k]
+		}
+	}
+	for n := range amount {
+		if 0 < amount[n] {
+			st.Stress[n] /= float64(amount[n])
+		}
+	}
+	st.Reactions = m.reactions(s.d, residual)
+	return
+}
+
+// reactions of supports by residual of internal forces of elements
+func (m Model) reactions(d dofs, residual []float64) (rs [][6]float64) {
+	for _, l := range m.Loads {
+		for dir := range l.Forces {
+			residual[6*l.Node+dir] -= l.Forces[dir]
+		}
+	}
+	// forces of slave nodes transfer to master node
+	for dof, ts := range d.slaves {
+		for _, t := range ts {
+			residual[t.eq] += t.c * residual[dof]
+		}
+		residual[dof] = 0
+	}
+	rs = make([][6]float64, len(m.Nodes))
+	for _, s := range m.Supports {
+		for dir := 0; dir < 6; dir++ {
+			if !s.Fixed[dir] && s.Stiffness[dir] == 0 {
+				continue
+			}
+			rs[s.Node][dir] = residual[6*s.Node+dir]
+		}
+	}
+	return
+}
@@ -0,0 +1,247 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+const (
+	E  = 2.0e11
+	G  = 8.0e10
+	A  = 1.0e-3
+	Iy = 2.0e-6
+	Iz = 1.0e-6
+	J  = 5.0e-7
+)
+
+// beams return chain of beams along axe X
+func beams(L float64, parts int) (nodes [][3]float64, bs []Beam) {
+	for i := 0; i <= parts; i++ {
+		nodes = append(nodes, [3]float64{L * float64(i) / float64(parts), 0, 0})
+	}
+	for i := 0; i < parts; i++ {
+		bs = append(bs, Beam{
+			Nodes: [2]int{i, i + 1},
+			E:     E, G: G, A: A, Iy: Iy, Iz: Iz, J: J,
+		})
+	}
+	return
+}
+
+var fixed = [6]bool{true, true, true, true, true, true}
+
+func isSame(t *testing.T, name string, act, exp float64) {
+	t.Helper()
+	if diff := math.Abs(act - exp); 1e-6*math.Abs(exp) < diff && 1e-12 < diff {
+		t.Errorf("%s: actual %.8e, expected %.8e", name, act, exp)
+	}
+}
+
+func TestCantilever(t *testing.T) {
+	const L, P = 2.0, 1000.0
+	var m Model
+	m.Nodes, m.Beams = beams(L, 4)
+	m.Supports = []Support{{Node: 0, Fixed: fixed}}
+	m.Loads = []Load{{Node: 4, Forces: [6]float64{0, 0, -P, 0, 0, 0}}}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	isSame(t, "Dz", st.Displacements[4][2], -P*L*L*L/(3*E*Iy))
+	isSame(t, "Ry", st.Displacements[4][4], P*L*L/(2*E*Iy))
+	isSame(t, "reaction Fz", st.Reactions[0][2], P)
+	isSame(t, "reaction My", st.Reactions[0][4], -P*L)
+	// moment at fixed end of first beam
+	isSame(t, "beam My", st.BeamForces[0][4], -P*L)
+}
+
+func TestSimplySupported(t *testing.T) {
+	const L, q = 6.0, -2000.0
+	var m Model
+	m.Nodes, m.Beams = beams(L, 2)
+	for i := range m.Beams {
+		m.Beams[i].Load = [3]float64{0, 0, q}
+	}
+	m.Supports = []Support{
+		{Node: 0, Fixed: [6]bool{true, true, true, true, false, false}},
+		{Node: 2, Fixed: [6]bool{false, true, true, false, false, false}},
+	}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	isSame(t, "Dz", st.Displacements[1][2], 5*q*math.Pow(L, 4)/(384*E*Iy))
+	isSame(t, "reaction 0", st.Reactions[0][2], -q*L/2)
+	isSame(t, "reaction 2", st.Reactions[2][2], -q*L/2)
+	// moment at middle of span
+	isSame(t, "My", st.BeamForces[0][10], q*L*L/8)
+}
+
+func TestRelease(t *testing.T) {
+	const L, P = 4.0, 500.0
+	var m Model
+	m.Nodes, m.Beams = beams(L, 2)
+	// hinge in middle
+	m.Beams[0].Release[1] = [6]bool{false, false, false, false, true, true}
+	m.Supports = []Support{
+		{Node: 0, Fixed: fixed},
+		{Node: 2, Fixed: [6]bool{true, true, true, true, false, false}},
+	}
+	m.Loads = []Load{{Node: 1, Forces: [6]float64{0, 0, -P, 0, 0, 0}}}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// right beam is pinned at both ends and without load,
+	// so load is carried by left beam as cantilever
+	isSame(t, "Dz", st.Displacements[1][2], -P*math.Pow(L/2, 3)/(3*E*Iy))
+	isSame(t, "reaction 0", st.Reactions[0][2], P)
+	isSame(t, "reaction 2", st.Reactions[2][2], 0)
+	isSame(t, "moment at hinge", st.BeamForces[0][10], 0)
+}
+
+func TestTruss(t *testing.T) {
+	// pinned beams without bending
+	const P = 1000.0
+	var m Model
+	m.Nodes = [][3]float64{{0, 0, 0}, {4, 0, 0}, {2, 0, 2}}
+	pinned := [6]bool{false, false, false, true, true, true}
+	for _, ns := range [][2]int{{0, 1}, {1, 2}, {2, 0}} {
+		m.Beams = append(m.Beams, Beam{
+			Nodes: ns,
+			E:     E, G: G, A: A, Iy: Iy, Iz: Iz, J: J,
+			Release: [2][6]bool{pinned, pinned},
+		})
+	}
+	m.Supports = []Support{
+		{Node: 0, Fixed: [6]bool{true, true, true, false, false, false}},
+		{Node: 1, Fixed: [6]bool{false, true, true, false, false, false}},
+		{Node: 2, Fixed: [6]bool{false, true, false, false, false, false}},
+	}
+	m.Loads = []Load{{Node: 2, Forces: [6]float64{0, 0, -P, 0, 0, 0}}}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	isSame(t, "reaction 0", st.Reactions[0][2], P/2)
+	isSame(t, "reaction 1", st.Reactions[1][2], P/2)
+	// compression in inclined beam
+	isSame(t, "axial", st.BeamForces[1][6], -P/2*math.Sqrt2)
+	isSame(t, "tension", st.BeamForces[0][6], P/2)
+}
+
+func TestSpring(t *testing.T) {
+	const L, P, k = 2.0, 1000.0, 1.0e6
+	var m Model
+	m.Nodes, m.Beams = beams(L, 1)
+	m.Supports = []Support{
+		{Node: 0, Fixed: fixed},
+		{Node: 1, Stiffness: [6]float64{0, 0, k, 0, 0, 0}},
+	}
+	m.Loads = []Load{{Node: 1, Forces: [6]float64{0, 0, -P, 0, 0, 0}}}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb := 3 * E * Iy / (L * L * L)
+	dz := -P / (k + kb)
+	isSame(t, "Dz", st.Displacements[1][2], dz)
+	isSame(t, "spring", st.Reactions[1][2], -k*dz)
+	isSame(t, "reaction", st.Reactions[0][2], -kb*dz)
+}
+
+func TestDisplacement(t *testing.T) {
+	const L, d = 3.0, -0.01
+	var m Model
+	m.Nodes, m.Beams = beams(L, 3)
+	m.Supports = []Support{
+		{Node: 0, Fixed: fixed},
+		{
+			Node:         3,
+			Fixed:        [6]bool{false, false, true, false, false, false},
+			Displacement: [6]float64{0, 0, d, 0, 0, 0},
+		},
+	}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	isSame(t, "Dz", st.Displacements[3][2], d)
+	isSame(t, "reaction", st.Reactions[3][2], 3*E*Iy*d/(L*L*L))
+}
+
+func TestRigidLink(t *testing.T) {
+	const L, P, e = 2.0, 1000.0, 0.3
+	var m Model
+	m.Nodes, m.Beams = beams(L, 2)
+	m.Nodes = append(m.Nodes, [3]float64{L, e, 0})
+	m.Supports = []Support{{Node: 0, Fixed: fixed}}
+	m.Links = []Link{{Master: 2, Slaves: []int{3}, Direction: fixed}}
+	m.Loads = []Load{{Node: 3, Forces: [6]float64{0, 0, -P, 0, 0, 0}}}
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rx := -P * e * L / (G * J)
+	dz := -P * L * L * L / (3 * E * Iy)
+	isSame(t, "master Rx", st.Displacements[2][3], rx)
+	isSame(t, "master Dz", st.Displacements[2][2], dz)
+	isSame(t, "slave Dz", st.Displacements[3][2], dz+rx*e)
+	isSame(t, "reaction Mx", st.Reactions[0][3], P*e)
+}
+
+func TestFrame3d(t *testing.T) {
+	// portal frame in space with uniform loads
+	var m Model
+	m.Nodes = [][3]float64{
+		{0, 0, 0}, {5, 0, 0}, {5, 4, 0}, {0, 4, 0},
+		{0, 0, 3}, {5, 0, 3}, {5, 4, 3}, {0, 4, 3},
+	}
+	add := func(n1, n2 int, q [3]float64) {
+		m.Beams = append(m.Beams, Beam{
+			Nodes: [2]int{n1, n2},
+			E:     E, G: G, A: A, Iy: Iy, Iz: Iz, J: J,
+			Load: q,
+		})
+	}
+	var total [3]float64
+	for i := 0; i < 4; i++ {
+		add(i, i+4, [3]float64{0, 0, 0})
+		q := [3]float64{100, 0, -1000}
+		add(i+4, (i+1)%4+4, q)
+		L := math.Sqrt(
+			math.Pow(m.Nodes[i+4][0]-m.Nodes[(i+1)%4+4][0], 2) +
+				math.Pow(m.Nodes[i+4][1]-m.Nodes[(i+1)%4+4][1], 2))
+		for k := range total {
+			total[k] += q[k] * L
+		}
+		m.Supports = append(m.Supports, Support{Node: i, Fixed: fixed})
+	}
+	m.Loads = []Load{{Node: 6, Forces: [6]float64{0, 500, 0, 0, 0, 0}}}
+	total[1] += 500
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum [3]float64
+	for i := 0; i < 4; i++ {
+		for k := range sum {
+			sum[k] += st.Reactions[i][k]
+		}
+	}
+	for k := range sum {
+		isSame(t, fmt.Sprintf("equilibrium %d", k), sum[k], -total[k])
+	}
+}
+
+func TestMechanism(t *testing.T) {
+	var m Model
+	m.Nodes, m.Beams = beams(2, 2)
+	m.Supports = []Support{{Node: 0, Fixed: [6]bool{true, true, true, false, false, false}}}
+	if _, err := m.LinearStatic(); err == nil {
+		t.Fatalf("mechanism is not found")
+	} else {
+		t.Log(err)
+	}
+}
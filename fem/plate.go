@@ -0,0 +1,484 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+)
+
+// Plate is flat shell element with 3 or 4 nodes and 6 degrees of freedom
+// in each node. Triangle is DKT plate with constant strain membrane,
+// quadrilateral is MITC4 plate with bilinear membrane. Rotation around
+// normal is stabilized by drilling stiffness.
+//
+// Local axe z is normal of plate. Local axe x is from first to second
+// node for triangle and from middle of side 1-4 to middle of side 2-3 for
+// quadrilateral.
+type Plate struct {
+	Nodes []int
+
+	E, Nu     float64 // elastic modulus and Poisson's ratio
+	Thickness float64
+	Density   float64 // mass per volume
+
+	// Load is uniform distributed load per area in global coordinates
+	Load [3]float64
+}
+
+// drilling is factor of stiffness for rotation around normal
+const drilling = 1e-3
+
+// axes return local axes and local coordinates of plate nodes
+func (p Plate) axes(nodes [][3]float64) (r [3][3]float64, xy [][2]float64, err error) {
+	if n := len(p.Nodes); n != 3 && n != 4 {
+		err = fmt.Errorf("not valid amount of nodes: %d", n)
+		return
+	}
+	ps := make([][3]float64, len(p.Nodes))
+	var c [3]float64
+	for i, n := range p.Nodes {
+		ps[i] = nodes[n]
+		for k := range c {
+			c[k] += ps[i][k] / float64(len(ps))
+		}
+	}
+	sub := func(a, b [3]float64) [3]float64 {
+		return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+	}
+	var x, z [3]float64
+	if len(ps) == 3 {
+		x = sub(ps[1], ps[0])
+		z = cross(x, sub(ps[2], ps[0]))
+	} else {
+		x = sub(sub(ps[1], ps[0]), sub(ps[3], ps[2])) // (p2+p3) - (p1+p4)
+		z = cross(sub(ps[2], ps[0]), sub(ps[3], ps[1]))
+	}
+	if norm(z) == 0 || norm(x) == 0 {
+		err = fmt.Errorf("degenerate plate")
+		return
+	}
+	z = scale(z, 1/norm(z))
+	x = sub(x, scale(z, dot(x, z)))
+	x = scale(x, 1/norm(x))
+	y := cross(z, x)
+	r = [3][3]float64{x, y, z}
+	xy = make([][2]float64, len(ps))
+	for i := range ps {
+		d := sub(ps[i], c)
+		xy[i] = [2]float64{dot(x, d), dot(y, d)}
+	}
+	return
+}
+
+// membrane return elasticity matrix of membrane forces
+func (p Plate) membrane() [3][3]float64 {
+	c := p.E * p.Thickness / (1 - p.Nu*p.Nu)
+	return [3][3]float64{
+		{c, c * p.Nu, 0},
+		{c * p.Nu, c, 0},
+		{0, 0, c * (1 - p.Nu) / 2},
+	}
+}
+
+// bending return elasticity matrix of moments
+func (p Plate) bending() [3][3]float64 {
+	c := p.E * p.Thickness * p.Thickness * p.Thickness / (12 * (1 - p.Nu*p.Nu))
+	return [3][3]float64{
+		{c, c * p.Nu, 0},
+		{c * p.Nu, c, 0},
+		{0, 0, c * (1 - p.Nu) / 2},
+	}
+}
+
+// shear return elasticity factor of transverse shear forces
+func (p Plate) shear() float64 {
+	return 5.0 / 6.0 * p.E / (2 * (1 + p.Nu)) * p.Thickness
+}
+
+// btdb add factor * Bt * D * B to matrix with map of columns
+func btdb(k [][]float64, b [][]float64, d [3][3]float64, factor float64, cols []int) {
+	db := make([][]float64, len(b))
+	for i := range b {
+		db[i] = make([]float64, len(cols))
+		for j := range cols {
+			for m := range b {
+				db[i][j] += d[i][m] * b[m][j]
+			}
+		}
+	}
+	for i := range cols {
+		for j := range cols {
+			var s float64
+			for m := range b {
+				s += b[m][i] * db[m][j]
+			}
+			k[cols[i]][cols[j]] += factor * s
+		}
+	}
+}
+
+// point of plate with shape functions for membrane and bending
+type platePoint struct {
+	bm [][]float64 // membrane strains for u, v of nodes
+	bb [][]float64 // curvatures for w, rx, ry of nodes
+	bs [][]float64 // shear strains for w, rx, ry of nodes, last row is zero
+	dA float64     // area of integration point
+}
+
+// columns of degrees of freedom in local matrix
+func plateColumns(n int) (mc, bc, dc []int) {
+	for i := 0; i < n; i++ {
+		mc = append(mc, 6*i, 6*i+1)
+		bc = append(bc, 6*i+2, 6*i+3, 6*i+4)
+		dc = append(dc, 6*i+5)
+	}
+	return
+}
+
+// triangle return points of integration or recovery for DKT and
+// constant strain triangle. Natural coordinates of nodes is (0,0), (1,0),
+// (0,1).
+func triangle(xy [][2]float64, points [][2]float64) (pps []platePoint, area float64) {
+	x := func(i, j int) float64 { return xy[i-1][0] - xy[j-1][0] }
+	y := func(i, j int) float64 { return xy[i-1][1] - xy[j-1][1] }
+	A2 := x(3, 1)*y(1, 2) - x(1, 2)*y(3, 1)
+	area = A2 / 2
+
+	// membrane
+	b := [3]float64{y(2, 3) / A2, y(3, 1) / A2, y(1, 2) / A2}
+	c := [3]float64{x(3, 2) / A2, x(1, 3) / A2, x(2, 1) / A2}
+	bm := [][]float64{make([]float64, 6), make([]float64, 6), make([]float64, 6)}
+	for i := 0; i < 3; i++ {
+		bm[0][2*i] = b[i]
+		bm[1][2*i+1] = c[i]
+		bm[2][2*i] = c[i]
+		bm[2][2*i+1] = b[i]
+	}
+
+	// DKT coefficients for sides 23, 31, 12
+	var P, q, t, r [7]float64
+	for k, side := range map[int][2]int{4: {2, 3}, 5: {3, 1}, 6: {1, 2}} {
+		xij, yij := x(side[0], side[1]), y(side[0], side[1])
+		l2 := xij*xij + yij*yij
+		P[k] = -6 * xij / l2
+		q[k] = 3 * xij * yij / l2
+		t[k] = -6 * yij / l2
+		r[k] = 3 * yij * yij / l2
+	}
+	for _, pt := range points {
+		xi, eta := pt[0], pt[1]
+		Hxx := []float64{
+			P[6]*(1-2*xi) + (P[5]-P[6])*eta,
+			q[6]*(1-2*xi) - (q[5]+q[6])*eta,
+			-4 + 6*(xi+eta) + r[6]*(1-2*xi) - eta*(r[5]+r[6]),
+			-P[6]*(1-2*xi) + eta*(P[4]+P[6]),
+			q[6]*(1-2*xi) - eta*(q[6]-q[4]),
+			-2 + 6*xi + r[6]*(1-2*xi) + eta*(r[4]-r[6]),
+			-eta * (P[5] + P[4]),
+			eta * (q[4] - q[5]),
+			-eta * (r[5] - r[4]),
+		}
+		Hyx := []float64{
+			t[6]*(1-2*xi) + eta*(t[5]-t[6]),
+			1 + r[6]*(1-2*xi) - eta*(r[5]+r[6]),
+			-q[6]*(1-2*xi) + eta*(q[5]+q[6]),
+			-t[6]*(1-2*xi) + eta*(t[4]+t[6]),
+			-1 + r[6]*(1-2*xi) + eta*(r[4]-r[6]),
+			-q[6]*(1-2*xi) - eta*(q[4]-q[6]),
+			-eta * (t[4] + t[5]),
+			eta * (r[4] - r[5]),
+			-eta * (q[4] - q[5]),
+		}
+		Hxe := []float64{
+			-P[5]*(1-2*eta) - xi*(P[6]-P[5]),
+			q[5]*(1-2*eta) - xi*(q[5]+q[6]),
+			-4 + 6*(xi+eta) + r[5]*(1-2*eta) - xi*(r[5]+r[6]),
+			xi * (P[4] + P[6]),
+			xi * (q[4] - q[6]),
+			-xi * (r[6] - r[4]),
+			P[5]*(1-2*eta) - xi*(P[4]+P[5]),
+			q[5]*(1-2*eta) + xi*(q[4]-q[5]),
+			-2 + 6*eta + r[5]*(1-2*eta) + xi*(r[4]-r[5]),
+		}
+		Hye := []float64{
+			-t[5]*(1-2*eta) - xi*(t[6]-t[5]),
+			1 + r[5]*(1-2*eta) - xi*(r[5]+r[6]),
+			-q[5]*(1-2*eta) + xi*(q[5]+q[6]),
+			xi * (t[4] + t[6]),
+			xi * (r[4] - r[6]),
+			-xi * (q[4] - q[6]),
+			t[5]*(1-2*eta) - xi*(t[4]+t[5]),
+			-1 + r[5]*(1-2*eta) + xi*(r[4]-r[5]),
+			-q[5]*(1-2*eta) - xi*(q[4]-q[5]),
+		}
+		bb := [][]float64{make([]float64, 9), make([]float64, 9), make([]float64, 9)}
+		for i := 0; i < 9; i++ {
+			bb[0][i] = (y(3, 1)*Hxx[i] + y(1, 2)*Hxe[i]) / A2
+			bb[1][i] = (-x(3, 1)*Hyx[i] - x(1, 2)*Hye[i]) / A2
+			bb[2][i] = (-x(3, 1)*Hxx[i] - x(1, 2)*Hxe[i] + y(3, 1)*Hyx[i] + y(1, 2)*Hye[i]) / A2
+		}
+		pps = append(pps, platePoint{bm: bm, bb: bb, dA: area / float64(len(points))})
+	}
+	return
+}
+
+// quadrilateral return points of integration or recovery for MITC4 and
+// bilinear membrane. Natural coordinates of nodes is (-1,-1), (1,-1),
+// (1,1), (-1,1).
+func quadrilateral(xy [][2]float64, points [][2]float64, weight float64) (pps []platePoint, err error) {
+	nat := [4][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}
+	shape := func(xi, eta float64) (n, dxi, deta [4]float64) {
+		for i := range nat {
+			n[i] = (1 + nat[i][0]*xi) * (1 + nat[i][1]*eta) / 4
+			dxi[i] = nat[i][0] * (1 + nat[i][1]*eta) / 4
+			deta[i] = nat[i][1] * (1 + nat[i][0]*xi) / 4
+		}
+		return
+	}
+	jacobian := func(dxi, deta [4]float64) (j [2][2]float64) {
+		for i := range xy {
+			j[0][0] += dxi[i] * xy[i][0]
+			j[0][1] += dxi[i] * xy[i][1]
+			j[1][0] += deta[i] * xy[i][0]
+			j[1][1] += deta[i] * xy[i][1]
+		}
+		return
+	}
+	// covariant shear strain along natural direction
+	covariant := func(xi, eta float64, dir int) (g []float64) {
+		n, dxi, deta := shape(xi, eta)
+		j := jacobian(dxi, deta)
+		d := dxi
+		if dir == 1 {
+			d = deta
+		}
+		g = make([]float64, 12)
+		for i := 0; i < 4; i++ {
+			g[3*i] = d[i]
+			// beta x = ry, beta y = -rx
+			g[3*i+1] = -n[i] * j[dir][1]
+			g[3*i+2] = n[i] * j[dir][0]
+		}
+		return
+	}
+	gA, gC := covariant(0, 1, 0), covariant(0, -1, 0)
+	gB, gD := covariant(1, 0, 1), covariant(-1, 0, 1)
+	for _, pt := range points {
+		xi, eta := pt[0], pt[1]
+		_, dxi, deta := shape(xi, eta)
+		j := jacobian(dxi, deta)
+		det := j[0][0]*j[1][1] - j[0][1]*j[1][0]
+		if det <= 0 {
+			err = fmt.Errorf("not valid geometry of quadrilateral")
+			return
+		}
+		var dx, dy [4]float64
+		for i := range dx {
+			dx[i] = (j[1][1]*dxi[i] - j[0][1]*deta[i]) / det
+			dy[i] = (-j[1][0]*dxi[i] + j[0][0]*deta[i]) / det
+		}
+		bm := [][]float64{make([]float64, 8), make([]float64, 8), make([]float64, 8)}
+		bb := [][]float64{make([]float64, 12), make([]float64, 12), make([]float64, 12)}
+		for i := 0; i < 4; i++ {
+			bm[0][2*i] = dx[i]
+			bm[1][2*i+1] = dy[i]
+			bm[2][2*i] = dy[i]
+			bm[2][2*i+1] = dx[i]
+			// curvatures by rotations: beta x = ry, beta y = -rx
+			bb[0][3*i+2] = dx[i]
+			bb[1][3*i+1] = -dy[i]
+			bb[2][3*i+1] = -dx[i]
+			bb[2][3*i+2] = dy[i]
+		}
+		// assumed covariant shear strains
+		bs := [][]float64{make([]float64, 12), make([]float64, 12), make([]float64, 12)}
+		for i := 0; i < 12; i++ {
+			gxi := (1+eta)/2*gA[i] + (1-eta)/2*gC[i]
+			geta := (1+xi)/2*gB[i] + (1-xi)/2*gD[i]
+			bs[0][i] = (j[1][1]*gxi - j[0][1]*geta) / det
+			bs[1][i] = (-j[1][0]*gxi + j[0][0]*geta) / det
+		}
+		pps = append(pps, platePoint{bm: bm, bb: bb, bs: bs, dA: det * weight})
+	}
+	return
+}
+
+// gauss points for quadrilateral
+var gauss2x2 = func() (ps [][2]float64) {
+	g := 1 / math.Sqrt(3)
+	return [][2]float64{{-g, -g}, {g, -g}, {g, g}, {-g, g}}
+}()
+
+// points of plate nodes in natural coordinates
+var (
+	triangleNodes      = [][2]float64{{0, 0}, {1, 0}, {0, 1}}
+	quadrilateralNodes = [][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}
+)
+
+// local return stiffness matrix in local coordinates
+func (p Plate) local(xy [][2]float64) (k [][]float64, area float64, err error) {
+	n := len(xy)
+	k = matrix(6 * n)
+	mc, bc, dc := plateColumns(n)
+	var pps []platePoint
+	if n == 3 {
+		pps, area = triangle(xy, [][2]float64{{1. / 6, 1. / 6}, {2. / 3, 1. / 6}, {1. / 6, 2. / 3}})
+		if area <= 0 {
+			err = fmt.Errorf("degenerate plate")
+			return
+		}
+	} else {
+		pps, err = quadrilateral(xy, gauss2x2, 1)
+		if err != nil {
+			return
+		}
+		for _, pp := range pps {
+			area += pp.dA
+		}
+	}
+	dm, db := p.membrane(), p.bending()
+	ds := p.shear()
+	for _, pp := range pps {
+		btdb(k, pp.bm, dm, pp.dA, mc)
+		btdb(k, pp.bb, db, pp.dA, bc)
+		if pp.bs != nil {
+			d := [3][3]float64{{ds, 0, 0}, {0, ds, 0}}
+			btdb(k, pp.bs, d, pp.dA, bc)
+		}
+	}
+	// drilling stiffness by difference between rotation of node and
+	// rotation of membrane in center of plate
+	var center []platePoint
+	if n == 3 {
+		center, _ = triangle(xy, [][2]float64{{1. / 3, 1. / 3}})
+	} else {
+		center, _ = quadrilateral(xy, [][2]float64{{0, 0}}, 1)
+	}
+	bm := center[0].bm
+	kd := drilling * p.E * p.Thickness * area
+	for i := 0; i < n; i++ {
+		g := make([]float64, 6*n)
+		g[dc[i]] = 1
+		for j := 0; j < n; j++ {
+			// rotation = (dv/dx - du/dy) / 2
+			g[mc[2*j]] -= -bm[2][2*j] / 2
+			g[mc[2*j+1]] -= bm[2][2*j+1] / 2
+		}
+		for a := range g {
+			if g[a] == 0 {
+				continue
+			}
+			for b := range g {
+				k[a][b] += kd * g[a] * g[b]
+			}
+		}
+	}
+	return
+}
+
+// stiffness return stiffness matrix and equivalent nodal loads
+// in global coordinates
+func (p Plate) stiffness(nodes [][3]float64) (k [][]float64, f []float64, err error) {
+	r, xy, err := p.axes(nodes)
+	if err != nil {
+		return
+	}
+	kl, area, err := p.local(xy)
+	if err != nil {
+		return
+	}
+	t := transformation(r, len(xy))
+	k = tmt(t, kl)
+	// load distributed by area of nodes
+	f = make([]float64, 6*len(xy))
+	var parts []float64
+	if len(xy) == 3 {
+		parts = []float64{area / 3, area / 3, area / 3}
+	} else {
+		parts = make([]float64, 4)
+		pps, _ := quadrilateral(xy, gauss2x2, 1)
+		for g, pp := range pps {
+			for i := range parts {
+				n := (1 + quadrilateralNodes[i][0]*gauss2x2[g][0]) *
+					(1 + quadrilateralNodes[i][1]*gauss2x2[g][1]) / 4
+				parts[i] += n * pp.dA
+			}
+		}
+	}
+	for i := range parts {
+		for d := 0; d < 3; d++ {
+			f[6*i+d] = p.Load[d] * parts[i]
+		}
+	}
+	return
+}
+
+// forces return forces in nodes of plate in local coordinates of plate:
+// Nx, Ny, Nxy, Mx, My, Mxy, Qx, Qy
+func (p Plate) forces(nodes [][3]float64, u []float64) (fs [][8]float64, err error) {
+	r, xy, err := p.axes(nodes)
+	if err != nil {
+		return
+	}
+	t := transformation(r, len(xy))
+	ul := mv(t, u)
+	n := len(xy)
+	mc, bc, _ := plateColumns(n)
+	var pps []platePoint
+	if n == 3 {
+		pps, _ = triangle(xy, triangleNodes)
+	} else {
+		pps, err = quadrilateral(xy, quadrilateralNodes, 1)
+		if err != nil {
+			return
+		}
+	}
+	product := func(b [][]float64, cols []int) (e []float64) {
+		e = make([]float64, len(b))
+		for i := range b {
+			for j := range cols {
+				e[i] += b[i][j] * ul[cols[j]]
+			}
+		}
+		return
+	}
+	dm, db, ds := p.membrane(), p.bending(), p.shear()
+	fs = make([][8]float64, n)
+	for i, pp := range pps {
+		em := product(pp.bm, mc)
+		eb := product(pp.bb, bc)
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				fs[i][a] += dm[a][b] * em[b]
+				fs[i][3+a] += db[a][b] * eb[b]
+			}
+		}
+		if pp.bs != nil {
+			es := product(pp.bs, bc)
+			fs[i][6] = ds * es[0]
+			fs[i][7] = ds * es[1]
+		}
+	}
+	return
+}
+
+// stress return maximal von Mises stress on surfaces of plate
+// for forces of plate
+func (p Plate) stress(f [8]float64) (s float64) {
+	t := p.Thickness
+	for _, side := range []float64{-1, 1} {
+		sx := f[0]/t + side*6*f[3]/(t*t)
+		sy := f[1]/t + side*6*f[4]/(t*t)
+		txy := f[2]/t + side*6*f[5]/(t*t)
+		s = math.Max(s, math.Sqrt(sx*sx-sx*sy+sy*sy+3*txy*txy))
+	}
+	return
+}
+
+func (p Plate) dofs() (g []int) {
+	for _, n := range p.Nodes {
+		for d := 0; d < 6; d++ {
+			g = append(g, 6*n+d)
+		}
+	}
+	return
+}
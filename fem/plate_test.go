@@ -0,0 +1,195 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// square return mesh of square plate a x a in plane XY with n x n
+// divisions by quadrilaterals or triangles
+func square(a float64, n int, triangles bool) (nodes [][3]float64, ps []Plate) {
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= n; j++ {
+			nodes = append(nodes, [3]float64{a * float64(j) / float64(n), a * float64(i) / float64(n), 0})
+		}
+	}
+	id := func(i, j int) int { return i*(n+1) + j }
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			n1, n2, n3, n4 := id(i, j), id(i, j+1), id(i+1, j+1), id(i+1, j)
+			if triangles {
+				ps = append(ps, Plate{Nodes: []int{n1, n2, n3}}, Plate{Nodes: []int{n1, n3, n4}})
+				continue
+			}
+			ps = append(ps, Plate{Nodes: []int{n1, n2, n3, n4}})
+		}
+	}
+	return
+}
+
+func TestPlateBending(t *testing.T) {
+	const (
+		a  = 1.0
+		n  = 16
+		th = 0.01
+		nu = 0.3
+		q  = -1000.0
+	)
+	D := E * th * th * th / (12 * (1 - nu*nu))
+	for _, tc := range []struct {
+		clamped bool
+		factor  float64
+	}{
+		{false, 0.00406},
+		{true, 0.00126},
+	} {
+		for _, triangles := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%v/%v", tc.clamped, triangles), func(t *testing.T) {
+				var m Model
+				m.Nodes, m.Plates = square(a, n, triangles)
+				for i := range m.Plates {
+					m.Plates[i].E = E
+					m.Plates[i].Nu = nu
+					m.Plates[i].Thickness = th
+					m.Plates[i].Load = [3]float64{0, 0, q}
+				}
+				for i, c := range m.Nodes {
+					s := Support{Node: i}
+					// membrane is free of load
+					s.Fixed[0], s.Fixed[1], s.Fixed[5] = true, true, true
+					onX := c[0] == 0 || c[0] == a
+					onY := c[1] == 0 || c[1] == a
+					if onX || onY {
+						s.Fixed[2] = true
+					}
+					if tc.clamped {
+						s.Fixed[3] = s.Fixed[3] || onX || onY
+						s.Fixed[4] = s.Fixed[4] || onX || onY
+					} else {
+						s.Fixed[3] = s.Fixed[3] || onX
+						s.Fixed[4] = s.Fixed[4] || onY
+					}
+					m.Supports = append(m.Supports, s)
+				}
+				st, err := m.LinearStatic()
+				if err != nil {
+					t.Fatal(err)
+				}
+				center := n/2*(n+1) + n/2
+				w := st.Displacements[center][2]
+				exp := tc.factor * q * math.Pow(a, 4) / D
+				if diff := math.Abs((w - exp) / exp); 0.02 < diff {
+					t.Errorf("center deflection %e, expected %e, diff %.2f%%", w, exp, diff*100)
+				}
+				// equilibrium
+				var sum float64
+				for i := range st.Reactions {
+					sum += st.Reactions[i][2]
+				}
+				isSame(t, "reaction", sum, -q*a*a)
+				if st.Stress[center] <= 0 {
+					t.Errorf("not valid stress: %e", st.Stress[center])
+				}
+			})
+		}
+	}
+}
+
+func TestPlateMembrane(t *testing.T) {
+	const (
+		L, b, th = 4.0, 1.0, 0.02
+		F        = 1.0e4
+	)
+	for _, triangles := range []bool{false, true} {
+		var m Model
+		m.Nodes, m.Plates = square(1, 4, triangles)
+		for i := range m.Nodes {
+			m.Nodes[i][0] *= L
+			m.Nodes[i][1] *= b
+		}
+		for i := range m.Plates {
+			m.Plates[i].E = E
+			m.Plates[i].Thickness = th
+		}
+		var right []int
+		for i, c := range m.Nodes {
+			s := Support{Node: i}
+			// without bending
+			s.Fixed[2], s.Fixed[3], s.Fixed[4] = true, true, true
+			if c[0] == 0 {
+				s.Fixed[0] = true
+				if c[1] == 0 {
+					s.Fixed[1] = true
+				}
+			}
+			if c[0] == L {
+				right = append(right, i)
+			}
+			m.Supports = append(m.Supports, s)
+		}
+		// consistent loads on edge
+		for k, i := range right {
+			f := F / float64(len(right)-1)
+			if k == 0 || k == len(right)-1 {
+				f /= 2
+			}
+			m.Loads = append(m.Loads, Load{Node: i, Forces: [6]float64{f, 0, 0, 0, 0, 0}})
+		}
+		st, err := m.LinearStatic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range right {
+			isSame(t, "Dx", st.Displacements[i][0], F*L/(E*b*th))
+		}
+		isSame(t, "Nx", st.PlateForces[0][0][0], F/b)
+	}
+}
+
+func TestPlateWithBeams(t *testing.T) {
+	// plate on beams at edges, loaded by uniform load
+	const (
+		a, th, q = 2.0, 0.01, -2000.0
+		n        = 4
+	)
+	var m Model
+	m.Nodes, m.Plates = square(a, n, false)
+	for i := range m.Plates {
+		m.Plates[i].E = E
+		m.Plates[i].Nu = 0.3
+		m.Plates[i].Thickness = th
+		m.Plates[i].Load = [3]float64{0, 0, q}
+	}
+	// beams along edges y = 0 and y = a
+	for _, row := range []int{0, n} {
+		for j := 0; j < n; j++ {
+			m.Beams = append(m.Beams, Beam{
+				Nodes: [2]int{row*(n+1) + j, row*(n+1) + j + 1},
+				E:     E, G: G, A: A, Iy: Iy, Iz: Iz, J: J,
+			})
+		}
+	}
+	for _, row := range []int{0, n} {
+		m.Supports = append(m.Supports,
+			Support{Node: row * (n + 1), Fixed: [6]bool{true, true, true, false, false, false}},
+			Support{Node: row*(n+1) + n, Fixed: [6]bool{false, true, true, true, false, false}},
+		)
+	}
+	m.Supports[0].Fixed[0] = true
+	m.Supports[1].Fixed[0] = true
+	st, err := m.LinearStatic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum [6]float64
+	for i := range st.Reactions {
+		for k := range sum {
+			sum[k] += st.Reactions[i][k]
+		}
+	}
+	isSame(t, "Fz", sum[2], -q*a*a)
+	if 0 <= st.Displacements[(n/2)*(n+1)+n/2][2] {
+		t.Errorf("not valid deflection")
+	}
+}
@@ -0,0 +1,234 @@
+package fem
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// sparse is symmetric matrix with storage of lower triangle only
+type sparse struct {
+	n    int
+	rows []map[int]float64 // rows[i][j] for j <= i
+}
+
+func newSparse(n int) *sparse {
+	s := &sparse{n: n, rows: make([]map[int]float64, n)}
+	for i := range s.rows {
+		s.rows[i] = map[int]float64{}
+	}
+	return s
+}
+
+// add value to matrix position. Value with column after row is ignored,
+// because symmetric part is added by symmetric element matrix.
+func (s *sparse) add(i, j int, v float64) {
+	if i < j {
+		return
+	}
+	s.rows[i][j] += v
+}
+
+func (s *sparse) get(i, j int) float64 {
+	if i < j {
+		i, j = j, i
+	}
+	return s.rows[i][j]
+}
+
+// mul return matrix-vector product
+func (s *sparse) mul(x []float64) (y []float64) {
+	y = make([]float64, s.n)
+	for i := range s.rows {
+		for j, v := range s.rows[i] {
+			y[i] += v * x[j]
+			if i != j {
+				y[j] += v * x[i]
+			}
+		}
+	}
+	return
+}
+
+// rcm return reverse Cuthill-McKee order of equations for reduce
+// profile of matrix. order[new] = old.
+func (s *sparse) rcm() (order []int) {
+	adj := make([][]int, s.n)
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			if i == j {
+				continue
+			}
+			adj[i] = append(adj[i], j)
+			adj[j] = append(adj[j], i)
+		}
+	}
+	for i := range adj {
+		sort.Ints(adj[i])
+	}
+	visited := make([]bool, s.n)
+	// start points sorted by degree
+	starts := make([]int, s.n)
+	for i := range starts {
+		starts[i] = i
+	}
+	sort.SliceStable(starts, func(a, b int) bool {
+		return len(adj[starts[a]]) < len(adj[starts[b]])
+	})
+	for _, st := range starts {
+		if visited[st] {
+			continue
+		}
+		visited[st] = true
+		queue := []int{st}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			order = append(order, p)
+			var next []int
+			for _, c := range adj[p] {
+				if visited[c] {
+					continue
+				}
+				visited[c] = true
+				next = append(next, c)
+			}
+			sort.SliceStable(next, func(a, b int) bool {
+				return len(adj[next[a]]) < len(adj[next[b]])
+			})
+			queue = append(queue, next...)
+		}
+	}
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return
+}
+
+// singular is error of factorization for matrix without full rank
+type singular struct{ eq int }
+
+func (s singular) Error() string {
+	return fmt.Sprintf("singular matrix at equation %d", s.eq)
+}
+
+// skyline is LDLt factorization of symmetric matrix in profile storage
+type skyline struct {
+	n     int
+	order []int // order[new] = old
+	inv   []int // inv[old] = new
+	first []int // first column of row
+	rows  [][]float64
+	d     []float64
+}
+
+// factorize return LDLt factorization of symmetric matrix
+func (s *sparse) factorize() (sk *skyline, err error) {
+	sk = &skyline{n: s.n}
+	sk.order = s.rcm()
+	sk.inv = make([]int, s.n)
+	for i, o := range sk.order {
+		sk.inv[o] = i
+	}
+	sk.first = make([]int, s.n)
+	for i := range sk.first {
+		sk.first[i] = i
+	}
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			I, J := sk.inv[i], sk.inv[j]
+			if I < J {
+				I, J = J, I
+			}
+			if J < sk.first[I] {
+				sk.first[I] = J
+			}
+		}
+	}
+	sk.rows = make([][]float64, s.n)
+	for I := range sk.rows {
+		sk.rows[I] = make([]float64, I-sk.first[I]+1)
+	}
+	for i := range s.rows {
+		for j, v := range s.rows[i] {
+			I, J := sk.inv[i], sk.inv[j]
+			if I < J {
+				I, J = J, I
+			}
+			sk.rows[I][J-sk.first[I]] += v
+		}
+	}
+	sk.d = make([]float64, s.n)
+	for i := 0; i < s.n; i++ {
+		fi := sk.first[i]
+		ri := sk.rows[i]
+		diag := ri[i-fi]
+		// g[i][j] = L[i][j] * D[j]
+		for j := fi; j < i; j++ {
+			fj := sk.first[j]
+			rj := sk.rows[j]
+			sum := ri[j-fi]
+			for k := max(fi, fj); k < j; k++ {
+				sum -= ri[k-fi] * rj[k-fj]
+			}
+			ri[j-fi] = sum
+		}
+		di := diag
+		for j := fi; j < i; j++ {
+			g := ri[j-fi]
+			l := g / sk.d[j]
+			di -= l * g
+			ri[j-fi] = l
+		}
+		if math.Abs(di) <= 1e-12*math.Abs(diag) || di == 0 || math.IsNaN(di) {
+			return nil, singular{eq: sk.order[i]}
+		}
+		sk.d[i] = di
+		ri[i-fi] = 1
+	}
+	return
+}
+
+// solve return solution of system for right-hand side
+func (sk *skyline) solve(f []float64) (x []float64) {
+	y := make([]float64, sk.n)
+	for i := range y {
+		y[i] = f[sk.order[i]]
+	}
+	// forward: L z = f
+	for i := 0; i < sk.n; i++ {
+		fi := sk.first[i]
+		ri := sk.rows[i]
+		for j := fi; j < i; j++ {
+			y[i] -= ri[j-fi] * y[j]
+		}
+	}
+	// diagonal
+	for i := range y {
+		y[i] /= sk.d[i]
+	}
+	// backward: Lt x = y
+	for i := sk.n - 1; 0 <= i; i-- {
+		fi := sk.first[i]
+		ri := sk.rows[i]
+		for j := fi; j < i; j++ {
+			y[j] -= ri[j-fi] * y[i]
+		}
+	}
+	x = make([]float64, sk.n)
+	for i := range y {
+		x[sk.order[i]] = y[i]
+	}
+	return
+}
+
+// negatives return amount of negative pivots. By Sylvester's law of
+// inertia it is amount of negative eigenvalues of matrix.
+func (sk *skyline) negatives() (n int) {
+	for _, d := range sk.d {
+		if d < 0 {
+			n++
+		}
+	}
+	return
+}
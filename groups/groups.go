@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 
 	"github.com/Konstantin8105/ds"
+	etree "github.com/Konstantin8105/errors"
+	"github.com/Konstantin8105/tf"
 	"github.com/Konstantin8105/vl"
 )
 
@@ -95,6 +98,15 @@ const (
 	NamedIndex        GroupIndex = 80
 	NamedListIndex               = 100
 	NodeSupportsIndex            = 1000
+	LineReleasesIndex            = 1100
+	RigidLinksIndex              = 1200
+	MaterialIndex                = 2000
+	SectionIndex                 = 2100
+	ThicknessIndex               = 2200
+	NodeLoadsIndex               = 3000
+	LineLoadsIndex               = 3100
+	PlateLoadsIndex              = 3200
+	NodeMassesIndex              = 3300
 	MetaIndex                    = 10000
 	CopyIndex                    = 10100
 )
@@ -107,6 +119,24 @@ func (gi GroupIndex) String() string {
 		return "Named list"
 	case NodeSupportsIndex:
 		return "Node supports"
+	case LineReleasesIndex:
+		return "Line releases"
+	case RigidLinksIndex:
+		return "Rigid links"
+	case MaterialIndex:
+		return "Material"
+	case SectionIndex:
+		return "Section"
+	case ThicknessIndex:
+		return "Thickness"
+	case NodeLoadsIndex:
+		return "Node loads"
+	case LineLoadsIndex:
+		return "Line loads"
+	case PlateLoadsIndex:
+		return "Plate loads"
+	case NodeMassesIndex:
+		return "Node masses"
 	case MetaIndex:
 		return "Meta"
 	case CopyIndex:
@@ -123,6 +153,24 @@ func (gi GroupIndex) newInstance(root Mesh) (gr Group, ok bool) {
 		gr, ok = new(NamedList), true
 	case NodeSupportsIndex:
 		gr, ok = new(NodeSupports), true
+	case LineReleasesIndex:
+		gr, ok = new(LineReleases), true
+	case RigidLinksIndex:
+		gr, ok = new(RigidLinks), true
+	case MaterialIndex:
+		gr, ok = new(Material), true
+	case SectionIndex:
+		gr, ok = new(Section), true
+	case ThicknessIndex:
+		gr, ok = new(Thickness), true
+	case NodeLoadsIndex:
+		gr, ok = new(NodeLoads), true
+	case LineLoadsIndex:
+		gr, ok = new(LineLoads), true
+	case PlateLoadsIndex:
+		gr, ok = new(PlateLoads), true
+	case NodeMassesIndex:
+		gr, ok = new(NodeMasses), true
 	case MetaIndex:
 		gr, ok = new(Meta), true
 	case CopyIndex:
@@ -362,8 +410,13 @@ func NewGroupTree(mesh Mesh, closedApp *bool, actions *chan ds.Action) (gt vl.Wi
 		}
 	}
 
-	var updateTree func(detail Group)
+	var updateTree, refresh func(detail Group)
 	updateTree = func(detail Group) {
+		// groups is changed
+		mesh.Update(nil, nil)
+		refresh(detail)
+	}
+	refresh = func(detail Group) {
 		tree := treeNode(
 			mesh.GetRootGroup(), mesh,
 			updateTree,
@@ -381,7 +434,7 @@ func NewGroupTree(mesh Mesh, closedApp *bool, actions *chan ds.Action) (gt vl.Wi
 
 	// first update
 	initialization = func() {
-		updateTree(mesh.GetRootGroup())
+		refresh(mesh.GetRootGroup())
 	}
 
 	return
@@ -593,12 +646,84 @@ func (m *NamedList) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
 
 var dir = [6]string{"Dx", "Dy", "Dz", "Rx", "Ry", "Rz"}
 
+// SupportKind is type of node support in single direction
+type SupportKind uint8
+
+const (
+	Free         SupportKind = iota // direction is not supported
+	Fixed                           // direction is fixed
+	Spring                          // elastic support with stiffness
+	Displacement                    // prescribed displacement
+	endSupportKind
+)
+
+func (sk SupportKind) String() string {
+	switch sk {
+	case Free:
+		return "free"
+	case Fixed:
+		return "fixed"
+	case Spring:
+		return "spring"
+	case Displacement:
+		return "displacement"
+	}
+	return fmt.Sprintf("undefined:%02d", uint8(sk))
+}
+
+// Support of node in single direction
+type Support struct {
+	Kind SupportKind
+	// Value is stiffness for Spring or displacement for Displacement
+	Value float64
+}
+
+// MarshalJSON store free and fixed supports as boolean for
+// compatibility with files created before support kinds
+func (s Support) MarshalJSON() ([]byte, error) {
+	switch s.Kind {
+	case Free:
+		return []byte("false"), nil
+	case Fixed:
+		return []byte("true"), nil
+	}
+	type support Support
+	return json.Marshal(support(s))
+}
+
+// UnmarshalJSON parse boolean as free or fixed support
+func (s *Support) UnmarshalJSON(bs []byte) error {
+	switch strings.TrimSpace(string(bs)) {
+	case "false":
+		*s = Support{Kind: Free}
+		return nil
+	case "true":
+		*s = Support{Kind: Fixed}
+		return nil
+	}
+	type support Support
+	var v support
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	*s = Support(v)
+	return nil
+}
+
+func (s Support) String() string {
+	switch s.Kind {
+	case Free, Fixed:
+		return s.Kind.String()
+	}
+	return fmt.Sprintf("%s %.5g", s.Kind, s.Value)
+}
+
 var _ Group = new(NodeSupports)
 
 type NodeSupports struct {
 	Idable
 	Named
-	Direction [6]bool
+	Direction [6]Support
 	Nodes     []uint
 }
 
@@ -609,10 +734,14 @@ func (m NodeSupports) GetGroupIndex() GroupIndex {
 func (m NodeSupports) String() (name string) {
 	name += fmt.Sprintf("%s: ", m.Named.String())
 	for i := range m.Direction {
-		if !m.Direction[i] {
+		switch m.Direction[i].Kind {
+		case Free:
 			continue
+		case Fixed:
+			name += dir[i] + " "
+		default:
+			name += fmt.Sprintf("%s(%s) ", dir[i], m.Direction[i])
 		}
-		name += dir[i] + " "
 	}
 	name += fmt.Sprintf("for %d nodes", len(m.Nodes))
 	return
@@ -645,7 +774,278 @@ func (m *NodeSupports) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
 		list.Add(new(vl.Separator))
 	}
 	{
-		list.Add(vl.TextStatic("Fixed node support direction:"))
+		list.Add(vl.TextStatic("Node support direction, kind and value of stiffness or displacement:"))
+		var kinds []string
+		for k := Free; k < endSupportKind; k++ {
+			kinds = append(kinds, k.String())
+		}
+		var (
+			cbs [6]*vl.ComboBox
+			ins [6]*vl.InputBox
+		)
+		for i := range m.Direction {
+			var lh vl.ListH
+			lh.Add(vl.TextStatic(dir[i]))
+			cb := new(vl.ComboBox)
+			cb.Add(kinds...)
+			cb.SetPos(uint(m.Direction[i].Kind))
+			lh.Add(cb)
+			in := new(vl.InputBox)
+			in.SetText(fmt.Sprintf("%.5g", m.Direction[i].Value))
+			in.Filter(tf.Float)
+			lh.Add(in)
+			list.Add(&lh)
+			cbs[i], ins[i] = cb, in
+		}
+		var btn vl.Button
+		btn.SetText("Apply")
+		btn.OnClick = func() {
+			for i := range m.Direction {
+				kind := SupportKind(cbs[i].GetPos())
+				if endSupportKind <= kind {
+					continue
+				}
+				var value float64
+				if kind == Spring || kind == Displacement {
+					v, err := strconv.ParseFloat(strings.TrimSpace(ins[i].GetText()), 64)
+					if err != nil {
+						ins[i].SetText(fmt.Sprintf("%.5g", m.Direction[i].Value))
+						continue
+					}
+					value = v
+				}
+				m.Direction[i] = Support{Kind: kind, Value: value}
+			}
+			updateTree(m)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, true, false, &m.Nodes, nil, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+		// TODO update screen
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(LineReleases)
+
+// LineReleases is hinges at begin and end of Line2 elements.
+// Released direction is free for transfer of forces between
+// line end and node.
+type LineReleases struct {
+	Idable
+	Named
+	Begin    [6]bool // released directions at first node of line
+	End      [6]bool // released directions at second node of line
+	Elements []uint
+}
+
+func (m LineReleases) GetGroupIndex() GroupIndex {
+	return LineReleasesIndex
+}
+
+func (m LineReleases) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	for _, side := range []struct {
+		name string
+		dir  [6]bool
+	}{
+		{"begin", m.Begin},
+		{"end", m.End},
+	} {
+		var present bool
+		for i := range side.dir {
+			if !side.dir[i] {
+				continue
+			}
+			if !present {
+				name += side.name + " "
+				present = true
+			}
+			name += dir[i] + " "
+		}
+	}
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *LineReleases) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *LineReleases) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	for _, side := range []struct {
+		name string
+		dir  *[6]bool
+	}{
+		{"Released direction at line begin:", &m.Begin},
+		{"Released direction at line end:", &m.End},
+	} {
+		list.Add(vl.TextStatic(side.name))
+		d := side.dir
+		for i := range d {
+			i := i
+			var ch vl.CheckBox
+			ch.SetText(dir[i])
+			ch.Checked = d[i]
+			ch.OnChange = func() {
+				d[i] = ch.Checked
+				updateTree(m)
+			}
+			list.Add(&ch)
+		}
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+		// TODO update screen
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(RigidLinks)
+
+// RigidLinks is constraint between master node and slave nodes.
+// Linked direction of slave node is defined by displacements of
+// master node. All directions linked is rigid body.
+type RigidLinks struct {
+	Idable
+	Named
+	Master    uint    // master node or NoMaster
+	Direction [6]bool // linked directions
+	Nodes     []uint  // slave nodes
+}
+
+// NoMaster is master node of rigid links after removing of master node
+const NoMaster = ^uint(0)
+
+func (m RigidLinks) GetGroupIndex() GroupIndex {
+	return RigidLinksIndex
+}
+
+func (m RigidLinks) String() (name string) {
+	if m.Master == NoMaster {
+		name += fmt.Sprintf("%s: without master ", m.Named.String())
+	} else {
+		name += fmt.Sprintf("%s: master %d ", m.Named.String(), m.Master)
+	}
+	rigid := true
+	for i := range m.Direction {
+		rigid = rigid && m.Direction[i]
+	}
+	if rigid {
+		name += "rigid body "
+	} else {
+		for i := range m.Direction {
+			if !m.Direction[i] {
+				continue
+			}
+			name += dir[i] + " "
+		}
+	}
+	name += fmt.Sprintf("for %d nodes", len(m.Nodes))
+	return
+}
+
+func (m *RigidLinks) Update(updating func(nodes, elements *[]uint)) {
+	master := []uint{m.Master}
+	updating(&master, nil)
+	if len(master) == 1 {
+		m.Master = master[0]
+	} else {
+		// master node is removed
+		m.Master = NoMaster
+	}
+	updating(&m.Nodes, nil)
+}
+
+func (m *RigidLinks) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			nodes := m.Nodes
+			if m.Master != NoMaster {
+				nodes = append([]uint{m.Master}, nodes...)
+			}
+			m.root.Select(nodes, nil)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var lh vl.ListH
+		lh.Add(vl.TextStatic("Master node:"))
+		var master vl.Text
+		if m.Master == NoMaster {
+			master.SetText("-")
+		} else {
+			master.SetText(fmt.Sprintf("%d", m.Master))
+		}
+		lh.Add(&master)
+		var btn vl.Button
+		btn.SetText("Change")
+		btn.OnClick = func() {
+			nodes, _ := m.root.GetSelected()
+			if len(nodes) != 1 {
+				return
+			}
+			m.Master = nodes[0]
+			master.SetText(fmt.Sprintf("%d", m.Master))
+			updateTree(m)
+		}
+		lh.Add(&btn)
+		list.Add(&lh)
+		list.Add(new(vl.Separator))
+	}
+	{
+		list.Add(vl.TextStatic("Linked direction:"))
 		for i := range m.Direction {
 			i := i
 			var ch vl.CheckBox
@@ -670,6 +1070,600 @@ func (m *NodeSupports) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
 	return
 }
 
+// CheckRigidLinks return error for rigid links with chains and cycles
+// of master nodes. Master node cannot be slave node and slave node
+// cannot be linked to different master nodes.
+func CheckRigidLinks(root Group) error {
+	et := etree.New("check rigid links")
+	// master node of each slave node
+	masters := map[uint]uint{}
+	var links []*RigidLinks
+	var walk func(gr Group)
+	walk = func(gr Group) {
+		switch n := gr.(type) {
+		case *RigidLinks:
+			links = append(links, n)
+		case *Meta:
+			for i := range n.Groups {
+				walk(n.Groups[i])
+			}
+		}
+	}
+	walk(root)
+	for _, l := range links {
+		for _, s := range l.Nodes {
+			if s == l.Master {
+				_ = et.Add(fmt.Errorf("%s: node %d is master and slave", l.Name, s))
+				continue
+			}
+			if m, ok := masters[s]; ok && m != l.Master {
+				_ = et.Add(fmt.Errorf("%s: node %d is slave of masters %d and %d",
+					l.Name, s, m, l.Master))
+				continue
+			}
+			masters[s] = l.Master
+		}
+	}
+	for _, l := range links {
+		if _, ok := masters[l.Master]; !ok {
+			continue
+		}
+		// follow masters
+		chain := []uint{l.Master}
+		visited := map[uint]bool{l.Master: true}
+		cycle := false
+		for next, ok := masters[l.Master]; ok; next, ok = masters[next] {
+			chain = append(chain, next)
+			if visited[next] {
+				cycle = true
+				break
+			}
+			visited[next] = true
+		}
+		if cycle {
+			_ = et.Add(fmt.Errorf("%s: cycle of masters %v", l.Name, chain))
+		} else {
+			_ = et.Add(fmt.Errorf("%s: chain of masters %v", l.Name, chain))
+		}
+	}
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// addInputs add widgets for change float values by button
+func addInputs(list *vl.List, header string, names []string, values []*float64, update func()) {
+	list.Add(vl.TextStatic(header))
+	ins := make([]*vl.InputBox, len(values))
+	for i := range values {
+		var lh vl.ListH
+		lh.Add(vl.TextStatic(names[i]))
+		in := new(vl.InputBox)
+		in.SetText(fmt.Sprintf("%.5g", *values[i]))
+		in.Filter(tf.Float)
+		lh.Add(in)
+		list.Add(&lh)
+		ins[i] = in
+	}
+	var btn vl.Button
+	btn.SetText("Apply")
+	btn.OnClick = func() {
+		for i := range values {
+			v, err := strconv.ParseFloat(strings.TrimSpace(ins[i].GetText()), 64)
+			if err != nil {
+				ins[i].SetText(fmt.Sprintf("%.5g", *values[i]))
+				continue
+			}
+			*values[i] = v
+		}
+		update()
+	}
+	list.Add(&btn)
+}
+
+// nonZero return names with values for not zero values
+func nonZero(names []string, values []float64) (s string) {
+	for i := range values {
+		if values[i] == 0 {
+			continue
+		}
+		s += fmt.Sprintf("%s=%.5g ", names[i], values[i])
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(Material)
+
+// Material is isotropic linear elastic material of elements
+type Material struct {
+	Idable
+	Named
+	E        float64 // elastic modulus
+	Nu       float64 // Poisson's ratio
+	Density  float64 // mass per volume
+	Elements []uint
+}
+
+func (m Material) GetGroupIndex() GroupIndex {
+	return MaterialIndex
+}
+
+func (m Material) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += fmt.Sprintf("E=%.5g Nu=%.5g ", m.E, m.Nu)
+	if m.Density != 0 {
+		name += fmt.Sprintf("Density=%.5g ", m.Density)
+	}
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *Material) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *Material) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		addInputs(&list, "Material properties:",
+			[]string{"Elastic modulus:", "Poisson's ratio:", "Density:"},
+			[]*float64{&m.E, &m.Nu, &m.Density},
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(Section)
+
+// Section is cross-section of Line2 elements.
+// Local axes of section is defined by local axes of elements.
+type Section struct {
+	Idable
+	Named
+	A        float64 // area
+	Iy, Iz   float64 // moment of inertia around local axes
+	J        float64 // torsion moment of inertia
+	Elements []uint
+}
+
+func (m Section) GetGroupIndex() GroupIndex {
+	return SectionIndex
+}
+
+func (m Section) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += fmt.Sprintf("A=%.5g Iy=%.5g Iz=%.5g J=%.5g ", m.A, m.Iy, m.Iz, m.J)
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *Section) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *Section) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		addInputs(&list, "Section properties:",
+			[]string{"Area:", "Iy:", "Iz:", "J:"},
+			[]*float64{&m.A, &m.Iy, &m.Iz, &m.J},
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(Thickness)
+
+// Thickness is thickness of Triangle3 and Quadr4 elements
+type Thickness struct {
+	Idable
+	Named
+	Thickness float64
+	Elements  []uint
+}
+
+func (m Thickness) GetGroupIndex() GroupIndex {
+	return ThicknessIndex
+}
+
+func (m Thickness) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += fmt.Sprintf("t=%.5g ", m.Thickness)
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *Thickness) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *Thickness) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		addInputs(&list, "Plate properties:",
+			[]string{"Thickness:"},
+			[]*float64{&m.Thickness},
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var forces = [6]string{"Fx", "Fy", "Fz", "Mx", "My", "Mz"}
+
+var _ Group = new(NodeLoads)
+
+// NodeLoads is forces and moments in nodes in global coordinates
+type NodeLoads struct {
+	Idable
+	Named
+	Forces [6]float64
+	Nodes  []uint
+}
+
+func (m NodeLoads) GetGroupIndex() GroupIndex {
+	return NodeLoadsIndex
+}
+
+func (m NodeLoads) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += nonZero(forces[:], m.Forces[:])
+	name += fmt.Sprintf("for %d nodes", len(m.Nodes))
+	return
+}
+
+func (m *NodeLoads) Update(updating func(nodes, elements *[]uint)) {
+	updating(&m.Nodes, nil)
+}
+
+func (m *NodeLoads) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(m.Nodes, nil)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var vs []*float64
+		for i := range m.Forces {
+			vs = append(vs, &m.Forces[i])
+		}
+		addInputs(&list, "Loads in global coordinates:", forces[:], vs,
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, true, false, &m.Nodes, nil, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var distributed = [3]string{"qx", "qy", "qz"}
+
+var _ Group = new(LineLoads)
+
+// LineLoads is uniform distributed load per length of Line2 elements
+// in global coordinates
+type LineLoads struct {
+	Idable
+	Named
+	Load     [3]float64
+	Elements []uint
+}
+
+func (m LineLoads) GetGroupIndex() GroupIndex {
+	return LineLoadsIndex
+}
+
+func (m LineLoads) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += nonZero(distributed[:], m.Load[:])
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *LineLoads) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *LineLoads) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var vs []*float64
+		for i := range m.Load {
+			vs = append(vs, &m.Load[i])
+		}
+		addInputs(&list, "Uniform load in global coordinates:", distributed[:], vs,
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(PlateLoads)
+
+// PlateLoads is uniform distributed load per area of Triangle3 and
+// Quadr4 elements in global coordinates
+type PlateLoads struct {
+	Idable
+	Named
+	Load     [3]float64
+	Elements []uint
+}
+
+func (m PlateLoads) GetGroupIndex() GroupIndex {
+	return PlateLoadsIndex
+}
+
+func (m PlateLoads) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += nonZero(distributed[:], m.Load[:])
+	name += fmt.Sprintf("for %d elements", len(m.Elements))
+	return
+}
+
+func (m *PlateLoads) Update(updating func(nodes, elements *[]uint)) {
+	updating(nil, &m.Elements)
+}
+
+func (m *PlateLoads) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(nil, m.Elements)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var vs []*float64
+		for i := range m.Load {
+			vs = append(vs, &m.Load[i])
+		}
+		addInputs(&list, "Uniform load in global coordinates:", distributed[:], vs,
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, false, true, nil, &m.Elements, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Group = new(NodeMasses)
+
+// NodeMasses is point masses in nodes for all translations
+type NodeMasses struct {
+	Idable
+	Named
+	Mass  float64
+	Nodes []uint
+}
+
+func (m NodeMasses) GetGroupIndex() GroupIndex {
+	return NodeMassesIndex
+}
+
+func (m NodeMasses) String() (name string) {
+	name += fmt.Sprintf("%s: ", m.Named.String())
+	name += fmt.Sprintf("mass=%.5g ", m.Mass)
+	name += fmt.Sprintf("for %d nodes", len(m.Nodes))
+	return
+}
+
+func (m *NodeMasses) Update(updating func(nodes, elements *[]uint)) {
+	updating(&m.Nodes, nil)
+}
+
+func (m *NodeMasses) GetWidget(updateTree func(gr Group)) (w vl.Widget) {
+	var list vl.List
+	list.Compress()
+	defer func() {
+		w = &list
+	}()
+	{
+		n := m.Named.GetWidget(func(_ Group) {
+			updateTree(m)
+		})
+		list.Add(n)
+		list.Add(new(vl.Separator))
+	}
+	{
+		var btn vl.Button
+		btn.SetText("Select")
+		btn.OnClick = func() {
+			m.root.Select(m.Nodes, nil)
+		}
+		list.Add(&btn)
+		list.Add(new(vl.Separator))
+	}
+	{
+		addInputs(&list, "Point mass:",
+			[]string{"Mass:"},
+			[]*float64{&m.Mass},
+			func() { updateTree(m) },
+		)
+		list.Add(new(vl.Separator))
+	}
+	{
+		change := Change(m.root, true, false, &m.Nodes, nil, func() {
+			updateTree(m)
+		})
+		list.Add(change)
+		list.Add(new(vl.Separator))
+	}
+	return
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 
 type Copy struct {
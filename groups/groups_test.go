@@ -1,9 +1,11 @@
 package groups
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Konstantin8105/compare"
@@ -61,13 +63,114 @@ func Test(t *testing.T) {
 		var s NodeSupports
 		s.Name = "base support"
 		s.Nodes = []uint{23, 52, 12, 23, 34, 456, 57, 68, 79, 14, 25, 36, 47, 58, 69}
-		s.Direction = [6]bool{true, true, false, true, false, false}
+		s.Direction = [6]Support{{Kind: Fixed}, {Kind: Fixed}, {}, {Kind: Fixed}, {}, {}}
 		m.Groups = append(m.Groups, &s)
 		tcs = append(tcs, tc{
 			name:  fmt.Sprintf("%06d_example", s.GetGroupIndex()),
 			group: &s,
 		})
 		inits = append(inits, func() { s.ID = 0 })
+
+		var r LineReleases
+		r.Name = "pinned beams"
+		r.Elements = []uint{4, 8, 15, 16, 23, 42}
+		r.Begin = [6]bool{false, false, false, false, true, true}
+		r.End = [6]bool{false, false, false, true, true, true}
+		m.Groups = append(m.Groups, &r)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", r.GetGroupIndex()),
+			group: &r,
+		})
+		inits = append(inits, func() { r.ID = 0 })
+
+		var l RigidLinks
+		l.Name = "eccentric column"
+		l.Master = 7
+		l.Direction = [6]bool{true, true, true, true, true, true}
+		l.Nodes = []uint{12, 13, 14}
+		m.Groups = append(m.Groups, &l)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", l.GetGroupIndex()),
+			group: &l,
+		})
+		inits = append(inits, func() { l.ID = 0 })
+
+		var mat Material
+		mat.Name = "steel"
+		mat.E, mat.Nu, mat.Density = 2.05e11, 0.3, 7850
+		mat.Elements = []uint{4, 8, 15, 16, 23, 42}
+		m.Groups = append(m.Groups, &mat)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", mat.GetGroupIndex()),
+			group: &mat,
+		})
+		inits = append(inits, func() { mat.ID = 0 })
+
+		var sec Section
+		sec.Name = "pipe 114x4"
+		sec.A, sec.Iy, sec.Iz, sec.J = 1.382e-3, 2.096e-6, 2.096e-6, 4.192e-6
+		sec.Elements = []uint{4, 8, 15, 16, 23, 42}
+		m.Groups = append(m.Groups, &sec)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", sec.GetGroupIndex()),
+			group: &sec,
+		})
+		inits = append(inits, func() { sec.ID = 0 })
+
+		var th Thickness
+		th.Name = "slab"
+		th.Thickness = 0.2
+		th.Elements = []uint{101, 102, 103}
+		m.Groups = append(m.Groups, &th)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", th.GetGroupIndex()),
+			group: &th,
+		})
+		inits = append(inits, func() { th.ID = 0 })
+
+		var nl NodeLoads
+		nl.Name = "equipment"
+		nl.Forces = [6]float64{0, 0, -12000, 0, 350, 0}
+		nl.Nodes = []uint{12, 13}
+		m.Groups = append(m.Groups, &nl)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", nl.GetGroupIndex()),
+			group: &nl,
+		})
+		inits = append(inits, func() { nl.ID = 0 })
+
+		var ll LineLoads
+		ll.Name = "snow"
+		ll.Load = [3]float64{0, 0, -1500}
+		ll.Elements = []uint{15, 16}
+		m.Groups = append(m.Groups, &ll)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", ll.GetGroupIndex()),
+			group: &ll,
+		})
+		inits = append(inits, func() { ll.ID = 0 })
+
+		var pl PlateLoads
+		pl.Name = "floor"
+		pl.Load = [3]float64{0, 0, -3000}
+		pl.Elements = []uint{101, 102}
+		m.Groups = append(m.Groups, &pl)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", pl.GetGroupIndex()),
+			group: &pl,
+		})
+		inits = append(inits, func() { pl.ID = 0 })
+
+		var nm NodeMasses
+		nm.Name = "tank"
+		nm.Mass = 2500
+		nm.Nodes = []uint{12, 13}
+		m.Groups = append(m.Groups, &nm)
+		tcs = append(tcs, tc{
+			name:  fmt.Sprintf("%06d_example", nm.GetGroupIndex()),
+			group: &nm,
+		})
+		inits = append(inits, func() { nm.ID = 0 })
 		{
 			var sub Meta
 			sub.Name = "Submodel"
@@ -137,3 +240,106 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+func TestSupport(t *testing.T) {
+	// file before support kinds
+	old := `{"Name":"old","Direction":[true,false,true,false,false,true],"Nodes":[1,2]}`
+	var s NodeSupports
+	if err := json.Unmarshal([]byte(old), &s); err != nil {
+		t.Fatal(err)
+	}
+	expect := [6]Support{{Kind: Fixed}, {}, {Kind: Fixed}, {}, {}, {Kind: Fixed}}
+	if s.Direction != expect {
+		t.Fatalf("not valid direction: %v", s.Direction)
+	}
+	// round trip for all kinds
+	s.Direction[1] = Support{Kind: Spring, Value: 1.2e5}
+	s.Direction[3] = Support{Kind: Displacement, Value: -0.015}
+	bs, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p NodeSupports
+	if err := json.Unmarshal(bs, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Direction != s.Direction {
+		t.Fatalf("not same:\n%v\n%v", s.Direction, p.Direction)
+	}
+	if !strings.Contains(string(bs), `[true,{"Kind":2,"Value":120000},true,`) {
+		t.Errorf("not compatible: %s", string(bs))
+	}
+}
+
+func TestCheckRigidLinks(t *testing.T) {
+	link := func(master uint, nodes ...uint) *RigidLinks {
+		var l RigidLinks
+		l.Name = fmt.Sprintf("link%d", master)
+		l.Master = master
+		l.Direction = [6]bool{true, true, true, false, false, false}
+		l.Nodes = nodes
+		return &l
+	}
+	tcs := []struct {
+		name  string
+		links []Group
+		err   string
+	}{
+		{"valid", []Group{link(1, 2, 3), link(4, 5)}, ""},
+		{"self", []Group{link(1, 1, 2)}, "master and slave"},
+		{"chain", []Group{link(1, 2), link(2, 3)}, "chain of masters [2 1]"},
+		{"cycle", []Group{link(1, 2), link(2, 1)}, "cycle of masters"},
+		{"two masters", []Group{link(1, 3), link(2, 3)}, "slave of masters 1 and 2"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var m Meta
+			m.Groups = tc.links
+			err := CheckRigidLinks(&m)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("not found error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("not valid error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRigidLinksUpdate(t *testing.T) {
+	// remove node 1 and renumber other nodes
+	updating := func(nodes, elements *[]uint) {
+		var res []uint
+		for _, n := range *nodes {
+			switch {
+			case n == 1:
+			case 1 < n:
+				res = append(res, n-1)
+			default:
+				res = append(res, n)
+			}
+		}
+		*nodes = res
+	}
+	var l RigidLinks
+	l.Master = 3
+	l.Nodes = []uint{1, 2}
+	l.Update(updating)
+	if l.Master != 2 || fmt.Sprintf("%v", l.Nodes) != "[1]" {
+		t.Fatalf("not valid update: %d %v", l.Master, l.Nodes)
+	}
+	l.Master = 1
+	l.Update(updating)
+	if l.Master != NoMaster {
+		t.Fatalf("master is not removed: %d", l.Master)
+	}
+	if s := l.String(); !strings.Contains(s, "without master") {
+		t.Errorf("not valid name: %s", s)
+	}
+}
@@ -0,0 +1,169 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Konstantin8105/gog"
+)
+
+// Dimension is annotation of measurement between two points
+type Dimension struct {
+	From, To gog.Point3d
+	Text     string
+}
+
+// plateArea return area of triangle or quadrilateral
+func plateArea(ps []gog.Point3d) (area float64) {
+	for i := 2; i < len(ps); i++ {
+		area += norm(cross(vector(ps[0], ps[i-1]), vector(ps[0], ps[i]))) / 2
+	}
+	return
+}
+
+// centroid return average point
+func centroid(ps []gog.Point3d) (c gog.Point3d) {
+	for _, p := range ps {
+		for i := range c {
+			c[i] += p[i] / float64(len(ps))
+		}
+	}
+	return
+}
+
+// plateNormal return unit normal of plate by Newell method
+func plateNormal(ps []gog.Point3d) (n [3]float64) {
+	for i := range ps {
+		c := cross(ps[i], ps[(i+1)%len(ps)])
+		for k := range n {
+			n[k] += c[k]
+		}
+	}
+	if l := norm(n); 0 < l {
+		for k := range n {
+			n[k] /= l
+		}
+	}
+	return
+}
+
+// DistanceNodes return distance between 2 nodes
+func (mm *Model) DistanceNodes(n1, n2 uint) (distance float64, dim Dimension, ok bool) {
+	// check
+	if s := []uint{n1, n2}; !mm.isValidNodeId(s) {
+		logger.Printf("DistanceNodes: not valid node id: %v", s)
+		return
+	}
+	// actions
+	p1, p2 := mm.Coords[n1].Point3d, mm.Coords[n2].Point3d
+	distance = gog.Distance3d(p1, p2)
+	dim = Dimension{From: p1, To: p2, Text: fmt.Sprintf("%.4f", distance)}
+	return distance, dim, true
+}
+
+// DistanceLines return distance between 2 parallel lines
+func (mm *Model) DistanceLines(l1, l2 uint) (distance float64, dim Dimension, ok bool) {
+	// check
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := []uint{l1, l2}; !mm.isValidElementId(s, isLine) {
+		logger.Printf("DistanceLines: not valid lines id: %v", s)
+		return
+	}
+	// actions
+	a := mm.getPoint3d(l1)
+	b := mm.getPoint3d(l2)
+	if gog.ZeroLine3d(a[0], a[1]) || gog.ZeroLine3d(b[0], b[1]) ||
+		!gog.IsParallelLine3d(a[0], a[1], b[0], b[1]) {
+		logger.Printf("DistanceLines: lines is not parallel")
+		return
+	}
+	distance, position := lineParameter(a[0], a[1], b[0])
+	to := gog.PointLineRatio3d(a[0], a[1], position/gog.Distance3d(a[0], a[1]))
+	dim = Dimension{From: b[0], To: to, Text: fmt.Sprintf("%.4f", distance)}
+	return distance, dim, true
+}
+
+// DistancePlates return distance between 2 parallel plates
+func (mm *Model) DistancePlates(p1, p2 uint) (distance float64, dim Dimension, ok bool) {
+	// check
+	isPlate := func(t ElType) bool { return t == Triangle3 || t == Quadr4 }
+	if s := []uint{p1, p2}; !mm.isValidElementId(s, isPlate) {
+		logger.Printf("DistancePlates: not valid plates id: %v", s)
+		return
+	}
+	// actions
+	a := mm.getPoint3d(p1)
+	b := mm.getPoint3d(p2)
+	na, nb := plateNormal(a), plateNormal(b)
+	if norm(na) == 0 || norm(nb) == 0 || gog.Eps3D < norm(cross(na, nb)) {
+		logger.Printf("DistancePlates: plates is not parallel")
+		return
+	}
+	from := centroid(b)
+	h := dot(na, vector(from, a[0]))
+	var to gog.Point3d
+	for i := range to {
+		to[i] = from[i] + h*na[i]
+	}
+	distance = math.Abs(h)
+	dim = Dimension{From: from, To: to, Text: fmt.Sprintf("%.4f", distance)}
+	return distance, dim, true
+}
+
+// AngleLines return angle between 2 lines in degrees from 0 to 90
+func (mm *Model) AngleLines(l1, l2 uint) (angle float64, dim Dimension, ok bool) {
+	// check
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := []uint{l1, l2}; !mm.isValidElementId(s, isLine) {
+		logger.Printf("AngleLines: not valid lines id: %v", s)
+		return
+	}
+	// actions
+	a := mm.getPoint3d(l1)
+	b := mm.getPoint3d(l2)
+	va, vb := vector(a[0], a[1]), vector(b[0], b[1])
+	l := norm(va) * norm(vb)
+	if l == 0 {
+		logger.Printf("AngleLines: zero length line")
+		return
+	}
+	cos := math.Min(1, math.Abs(dot(va, vb))/l)
+	angle = math.Acos(cos) * 180 / math.Pi
+	dim = Dimension{From: centroid(a), To: centroid(b), Text: fmt.Sprintf("%.2f deg", angle)}
+	return angle, dim, true
+}
+
+// TotalLength return summary length of lines
+func (mm *Model) TotalLength(elements []uint) (length float64) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("TotalLength: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	for _, e := range elements {
+		if mm.Elements[e].ElementType != Line2 {
+			continue
+		}
+		ps := mm.getPoint3d(e)
+		length += gog.Distance3d(ps[0], ps[1])
+	}
+	return
+}
+
+// TotalArea return summary area of plates
+func (mm *Model) TotalArea(elements []uint) (area float64) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("TotalArea: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	for _, e := range elements {
+		if t := mm.Elements[e].ElementType; t != Triangle3 && t != Quadr4 {
+			continue
+		}
+		area += plateArea(mm.getPoint3d(e))
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package ms
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeasure(t *testing.T) {
+	var mm Model
+	for _, c := range [][3]float64{
+		{0, 0, 0}, {2, 0, 0}, // line 0
+		{0, 1, 0}, {2, 1, 0}, // line 1 parallel
+		{0, 0, 1}, {1, 0, 2}, // line 2 at 45 degree
+		{0, 2, 0}, {1, 2, 0}, {1, 3, 0}, // triangle 3
+		{0, 2, 3}, {1, 2, 3}, {1, 3, 3}, {0, 3, 3}, // quadrilateral 4
+	} {
+		mm.AddNode(c[0], c[1], c[2])
+	}
+	mm.AddLineByNodeNumber(0, 1)
+	mm.AddLineByNodeNumber(2, 3)
+	mm.AddLineByNodeNumber(4, 5)
+	mm.AddTriangle3ByNodeNumber(6, 7, 8)
+	mm.AddQuadr4ByNodeNumber(9, 10, 11, 12)
+	check := func(name string, act, exp float64, ok bool) {
+		t.Helper()
+		if !ok || 1e-9 < math.Abs(act-exp) {
+			t.Errorf("%s: %v != %v, %v", name, act, exp, ok)
+		}
+	}
+	d, _, ok := mm.DistanceNodes(0, 3)
+	check("nodes", d, math.Sqrt(5), ok)
+	d, dim, ok := mm.DistanceLines(0, 1)
+	check("lines", d, 1, ok)
+	check("dimension", dim.To[1], 0, ok)
+	if _, _, ok := mm.DistanceLines(0, 2); ok {
+		t.Errorf("not parallel lines")
+	}
+	d, _, ok = mm.DistancePlates(3, 4)
+	check("plates", d, 3, ok)
+	a, _, ok := mm.AngleLines(0, 2)
+	check("angle", a, 45, ok)
+	check("length", mm.TotalLength([]uint{0, 1, 3}), 4, true)
+	check("area", mm.TotalArea([]uint{0, 3, 4}), 1.5, true)
+}
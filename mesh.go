@@ -0,0 +1,907 @@
+package ms
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// plane is local coordinate system on plane
+type plane struct {
+	origin  gog.Point3d
+	u, v, n [3]float64 // unit axes, n is normal of plane
+}
+
+// newPlane return plane of points. Result is not valid for points on
+// one line or for points not on one plane.
+func newPlane(ps []gog.Point3d) (pl plane, ok bool) {
+	if len(ps) < 3 {
+		return
+	}
+	pl.origin = ps[0]
+	// first axe by farthest point
+	var size float64
+	for _, p := range ps {
+		if d := gog.Distance3d(pl.origin, p); size < d {
+			size = d
+			pl.u = vector(pl.origin, p)
+		}
+	}
+	if size < gog.Eps3D {
+		return
+	}
+	for i := range pl.u {
+		pl.u[i] /= size
+	}
+	// normal by farthest point from first axe
+	var h float64
+	for _, p := range ps {
+		c := cross(pl.u, vector(pl.origin, p))
+		if d := norm(c); h < d {
+			h = d
+			pl.n = c
+		}
+	}
+	if h < gog.Eps3D*math.Max(1, size) {
+		return
+	}
+	for i := range pl.n {
+		pl.n[i] /= h
+	}
+	pl.v = cross(pl.n, pl.u)
+	// all points on plane
+	for _, p := range ps {
+		if gog.Eps3D*math.Max(1, size) < math.Abs(dot(pl.n, vector(pl.origin, p))) {
+			return
+		}
+	}
+	return pl, true
+}
+
+// local return coordinates of point in plane
+func (pl plane) local(p gog.Point3d) gog.Point {
+	d := vector(pl.origin, p)
+	return gog.Point{X: dot(pl.u, d), Y: dot(pl.v, d)}
+}
+
+// global return point in global coordinates
+func (pl plane) global(p gog.Point) (g gog.Point3d) {
+	for i := range g {
+		g[i] = pl.origin[i] + p.X*pl.u[i] + p.Y*pl.v[i]
+	}
+	return
+}
+
+// loopEdges return indexes of edges on closed loops. Edges of not
+// closed chains are removed by degree of nodes.
+func loopEdges(edges [][2]int) (loops []int) {
+	removed := make([]bool, len(edges))
+	for changed := true; changed; {
+		changed = false
+		degree := map[int]int{}
+		for i, e := range edges {
+			if removed[i] {
+				continue
+			}
+			degree[e[0]]++
+			degree[e[1]]++
+		}
+		for i, e := range edges {
+			if removed[i] {
+				continue
+			}
+			if degree[e[0]] < 2 || degree[e[1]] < 2 {
+				removed[i] = true
+				changed = true
+			}
+		}
+	}
+	for i := range edges {
+		if !removed[i] {
+			loops = append(loops, i)
+		}
+	}
+	return
+}
+
+// isInside return true for point inside closed loops by even-odd rule
+func isInside(p gog.Point, ps []gog.Point, edges [][2]int) bool {
+	inside := false
+	for _, e := range edges {
+		a, b := ps[e[0]], ps[e[1]]
+		if (a.Y > p.Y) == (b.Y > p.Y) {
+			continue
+		}
+		if x := a.X + (p.Y-a.Y)/(b.Y-a.Y)*(b.X-a.X); p.X < x {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// triangulate return triangles of constrained Delaunay triangulation of
+// nodes and additional points on plane. Triangles is counterclockwise in
+// plane coordinates. New nodes are created for additional points and for
+// intersections of edges. If edges create closed loops, then triangles
+// only inside loops are returned.
+func (mm *Model) triangulate(
+	pl plane,
+	nodes []uint,
+	points []gog.Point,
+	edges [][2]uint,
+) (triangles [][3]uint, err error) {
+	var model gog.Model
+	index := map[uint]int{} // node to point of model
+	for _, n := range nodes {
+		index[n] = model.AddPoint(pl.local(mm.Coords[n].Point3d))
+	}
+	for _, p := range points {
+		model.AddPoint(p)
+	}
+	var lines [][2]int
+	for _, e := range edges {
+		a, b := index[e[0]], index[e[1]]
+		lines = append(lines, [2]int{a, b})
+		model.AddLine(model.Points[a], model.Points[b], gog.Fixed)
+	}
+	var loops [][2]int
+	for _, i := range loopEdges(lines) {
+		loops = append(loops, lines[i])
+	}
+	mesh, err := gog.New(model.Copy())
+	if err != nil {
+		return
+	}
+	// nodes of triangulation
+	ps := mesh.InternalModel.Points
+	ids := make([]uint, len(ps))
+	for i, p := range ps {
+		found := false
+		for _, n := range nodes {
+			if gog.SamePoints(p, model.Points[index[n]]) {
+				ids[i], found = n, true
+				break
+			}
+		}
+		if !found {
+			// additional point or intersection of lines
+			g := pl.global(p)
+			ids[i] = mm.AddNode(g[0], g[1], g[2])
+		}
+	}
+	for _, tr := range mesh.InternalModel.Triangles {
+		if tr[0] == gog.Removed {
+			continue
+		}
+		if 0 < len(loops) {
+			c := gog.Point{
+				X: (ps[tr[0]].X + ps[tr[1]].X + ps[tr[2]].X) / 3,
+				Y: (ps[tr[0]].Y + ps[tr[1]].Y + ps[tr[2]].Y) / 3,
+			}
+			if !isInside(c, model.Points, loops) {
+				continue
+			}
+		}
+		if gog.Orientation(ps[tr[0]], ps[tr[1]], ps[tr[2]]) == gog.ClockwisePoints {
+			tr[1], tr[2] = tr[2], tr[1]
+		}
+		triangles = append(triangles, [3]uint{ids[tr[0]], ids[tr[1]], ids[tr[2]]})
+	}
+	return
+}
+
+// Triangulation create constrained Delaunay triangulation of nodes on
+// one plane. Lines is constrained edges of triangulation. If lines
+// create closed loops, then triangles only inside loops are created and
+// inner loops is holes.
+func (mm *Model) Triangulation(nodes, lines []uint) (triangles []uint) {
+	// check
+	if s := nodes; !mm.isValidNodeId(s) {
+		logger.Printf("Triangulation: not valid node id: %v", s)
+		return
+	}
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := lines; !mm.isValidElementId(s, isLine) {
+		logger.Printf("Triangulation: not valid lines id: %v", s)
+		return
+	}
+	// actions
+	var edges [][2]uint
+	for _, l := range lines {
+		a, b := mm.Elements[l].Indexes[0], mm.Elements[l].Indexes[1]
+		edges = append(edges, [2]uint{uint(a), uint(b)})
+		nodes = append(nodes, uint(a), uint(b))
+	}
+	nodes = uniqUint(nodes)
+	var ps []gog.Point3d
+	for _, n := range nodes {
+		ps = append(ps, mm.Coords[n].Point3d)
+	}
+	pl, ok := newPlane(ps)
+	if !ok {
+		logger.Printf("Triangulation: nodes is not on one plane")
+		return
+	}
+	defer mm.DeselectAll()
+	trs, err := mm.triangulate(pl, nodes, nil, edges)
+	if err != nil {
+		logger.Printf("Triangulation: %v", err)
+		return
+	}
+	// normal of triangles is normal of plane
+	for _, tr := range trs {
+		if id, ok := mm.AddTriangle3ByNodeNumber(tr[0], tr[1], tr[2]); ok {
+			triangles = append(triangles, id)
+		}
+	}
+	return
+}
+
+// segmentDistance return distance between point and segment
+func segmentDistance(p, a, b gog.Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; 0 < l {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	}
+	return math.Hypot(p.X-a.X-t*dx, p.Y-a.Y-t*dy)
+}
+
+// pairTriangles return quadrilaterals by pairs of counterclockwise
+// triangles with common edge. Pairs with better angles are preferred and
+// only convex quadrilaterals with corners from 45 to 135 degrees and
+// warping below defaultWarping are created. Pairs is indexes of
+// triangles for each quadrilateral. Not paired triangles is rest.
+func pairTriangles(trs [][3]uint, point func(uint) gog.Point3d) (
+	quadrs [][4]uint,
+	pairs [][2]int,
+	rest [][3]uint,
+) {
+	type pair struct {
+		t1, t2 int
+		quadr  [4]uint
+		skew   float64
+	}
+	var candidates []pair
+	edges := map[[2]uint]int{}
+	for i, tr := range trs {
+		for k := 0; k < 3; k++ {
+			a, b := tr[k], tr[(k+1)%3]
+			j, ok := edges[[2]uint{b, a}]
+			if !ok {
+				edges[[2]uint{a, b}] = i
+				continue
+			}
+			// opposite node of other triangle
+			var d uint
+			for _, n := range trs[j] {
+				if n != a && n != b {
+					d = n
+				}
+			}
+			q := [4]uint{a, d, b, tr[(k+2)%3]}
+			var ps [4]gog.Point3d
+			for p := range q {
+				ps[p] = point(q[p])
+			}
+			if quadrNegativeCorners(ps) != 0 || defaultWarping < quadrWarping(ps) {
+				continue
+			}
+			skew := 0.0
+			for _, angle := range plateAngles(ps[:]) {
+				skew = math.Max(skew, math.Abs(angle-90))
+			}
+			if 45 <= skew {
+				continue
+			}
+			candidates = append(candidates, pair{t1: i, t2: j, quadr: q, skew: skew})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].skew < candidates[j].skew
+	})
+	used := make([]bool, len(trs))
+	for _, p := range candidates {
+		if used[p.t1] || used[p.t2] {
+			continue
+		}
+		used[p.t1], used[p.t2] = true, true
+		quadrs = append(quadrs, p.quadr)
+		pairs = append(pairs, [2]int{p.t1, p.t2})
+	}
+	for i, tr := range trs {
+		if !used[i] {
+			rest = append(rest, tr)
+		}
+	}
+	return
+}
+
+// MeshArea create mesh of plates inside closed loops of lines on one
+// plane. Inner loops is holes. Lines are split by size of elements and
+// boundary nodes are nodes of mesh. If quadr is true, then mesh is
+// mostly quadrilaterals, otherwise mesh is triangles.
+func (mm *Model) MeshArea(lines []uint, size float64, quadr bool) (elements []uint) {
+	// check
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := lines; !mm.isValidElementId(s, isLine) {
+		logger.Printf("MeshArea: not valid lines id: %v", s)
+		return
+	}
+	if !mm.isValidValue(size) || size <= 0 {
+		logger.Printf("MeshArea: not valid size: %v", size)
+		return
+	}
+	// actions
+	var edges [][2]uint
+	var nodes []uint
+	var ps []gog.Point3d
+	for _, l := range lines {
+		a, b := mm.Elements[l].Indexes[0], mm.Elements[l].Indexes[1]
+		edges = append(edges, [2]uint{uint(a), uint(b)})
+		nodes = append(nodes, uint(a), uint(b))
+		ps = append(ps, mm.Coords[a].Point3d, mm.Coords[b].Point3d)
+	}
+	pl, ok := newPlane(ps)
+	if !ok {
+		logger.Printf("MeshArea: lines is not on one plane")
+		return
+	}
+	// edges of closed loops
+	loops := func() (ls [][2]uint) {
+		var es [][2]int
+		for _, e := range edges {
+			es = append(es, [2]int{int(e[0]), int(e[1])})
+		}
+		for _, i := range loopEdges(es) {
+			ls = append(ls, edges[i])
+		}
+		return
+	}
+	if len(loops()) == 0 {
+		logger.Printf("MeshArea: lines is not closed loop")
+		return
+	}
+	defer mm.DeselectAll()
+	// split boundary lines by size
+	edges, nodes = nil, nil
+	for _, l := range lines {
+		ids := []uint{l}
+		length := gog.Distance3d(mm.getPoint3d(l)[0], mm.getPoint3d(l)[1])
+		if parts := uint(math.Round(length / size)); 1 < parts {
+			last := len(mm.Elements)
+			mm.SplitLinesByEqualParts([]uint{l}, parts)
+			for id := last; id < len(mm.Elements); id++ {
+				ids = append(ids, uint(id))
+			}
+		}
+		for _, id := range ids {
+			a, b := mm.Elements[id].Indexes[0], mm.Elements[id].Indexes[1]
+			edges = append(edges, [2]uint{uint(a), uint(b)})
+			nodes = append(nodes, uint(a), uint(b))
+		}
+	}
+	nodes = uniqUint(nodes)
+	// boundary in plane coordinates
+	var boundary [][2]gog.Point
+	for _, e := range loops() {
+		boundary = append(boundary, [2]gog.Point{
+			pl.local(mm.Coords[e[0]].Point3d),
+			pl.local(mm.Coords[e[1]].Point3d),
+		})
+	}
+	var local []gog.Point
+	var loopIndexes [][2]int
+	for _, e := range boundary {
+		local = append(local, e[0], e[1])
+		loopIndexes = append(loopIndexes, [2]int{len(local) - 2, len(local) - 1})
+	}
+	// direction of grid by longest boundary line
+	var ux, uy float64 = 1, 0
+	{
+		var longest float64
+		for _, e := range boundary {
+			if l := gog.Distance(e[0], e[1]); longest < l {
+				longest = l
+				ux, uy = (e[1].X-e[0].X)/l, (e[1].Y-e[0].Y)/l
+			}
+		}
+	}
+	toGrid := func(p gog.Point) gog.Point {
+		return gog.Point{X: p.X*ux + p.Y*uy, Y: -p.X*uy + p.Y*ux}
+	}
+	fromGrid := func(p gog.Point) gog.Point {
+		return gog.Point{X: p.X*ux - p.Y*uy, Y: p.X*uy + p.Y*ux}
+	}
+	var gs []gog.Point
+	for _, p := range local {
+		gs = append(gs, toGrid(p))
+	}
+	pmin, pmax := gog.BorderPoints2d(gs...)
+	// inner points on grid
+	dx, dy := size, size
+	if !quadr {
+		// equilateral triangles
+		dy = size * math.Sqrt(3) / 2
+	}
+	var points []gog.Point
+	for row := 1; pmin.Y+float64(row)*dy < pmax.Y; row++ {
+		shift := 0.0
+		if !quadr && row%2 == 1 {
+			shift = size / 2
+		}
+		for col := 0; pmin.X+shift+float64(col)*dx < pmax.X; col++ {
+			p := fromGrid(gog.Point{
+				X: pmin.X + shift + float64(col)*dx,
+				Y: pmin.Y + float64(row)*dy,
+			})
+			if !isInside(p, local, loopIndexes) {
+				continue
+			}
+			near := false
+			for _, e := range boundary {
+				if segmentDistance(p, e[0], e[1]) < size/2 {
+					near = true
+					break
+				}
+			}
+			if !near {
+				points = append(points, p)
+			}
+		}
+	}
+	trs, err := mm.triangulate(pl, nodes, points, edges)
+	if err != nil {
+		logger.Printf("MeshArea: %v", err)
+		return
+	}
+	if quadr {
+		var qs [][4]uint
+		qs, _, trs = pairTriangles(trs, func(n uint) gog.Point3d {
+			return mm.Coords[n].Point3d
+		})
+		for _, q := range qs {
+			if id, ok := mm.AddQuadr4ByNodeNumber(q[0], q[1], q[2], q[3]); ok {
+				elements = append(elements, id)
+			}
+		}
+	}
+	for _, tr := range trs {
+		if id, ok := mm.AddTriangle3ByNodeNumber(tr[0], tr[1], tr[2]); ok {
+			elements = append(elements, id)
+		}
+	}
+	return
+}
+
+// SmoothMesh move inner nodes of plates to average position of
+// neighbour nodes. Nodes on boundary of plates, supported nodes and nodes
+// of other elements are not moved. If guarded is true, then node is
+// moved only without inversion of plates and without decreasing of
+// minimal angle. Result before and after is minimal angle of plates in
+// degrees.
+func (mm *Model) SmoothMesh(plates []uint, iterations uint, guarded bool) (before, after float64) {
+	// check
+	isPlate := func(t ElType) bool { return t == Triangle3 || t == Quadr4 }
+	if s := plates; !mm.isValidElementId(s, isPlate) {
+		logger.Printf("SmoothMesh: not valid plates id: %v", s)
+		return
+	}
+	// actions
+	plates = uniqUint(append([]uint(nil), plates...))
+	minAngle := func(els []uint) (angle float64) {
+		angle = 180
+		for _, e := range els {
+			angle = math.Min(angle, quality(MinAngle, mm.getPoint3d(e)))
+		}
+		return
+	}
+	before = minAngle(plates)
+	after = before
+	if iterations == 0 || len(plates) == 0 {
+		return
+	}
+	defer mm.DeselectAll()
+	selected := map[uint]bool{}
+	for _, p := range plates {
+		selected[p] = true
+	}
+	// connections of nodes
+	fixed := map[int]bool{}
+	edges := map[[2]int]int{}
+	neighbours := map[int]map[int]bool{}
+	around := map[int][]uint{} // plates around node
+	for _, p := range plates {
+		idx := mm.Elements[p].Indexes
+		for k := range idx {
+			a, b := idx[k], idx[(k+1)%len(idx)]
+			if b < a {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+			for _, v := range [2][2]int{{a, b}, {b, a}} {
+				if neighbours[v[0]] == nil {
+					neighbours[v[0]] = map[int]bool{}
+				}
+				neighbours[v[0]][v[1]] = true
+			}
+			around[idx[k]] = append(around[idx[k]], p)
+		}
+	}
+	// boundary nodes
+	for e, amount := range edges {
+		if amount == 1 {
+			fixed[e[0]], fixed[e[1]] = true, true
+		}
+	}
+	// nodes of other elements
+	for i, el := range mm.Elements {
+		if el.ElementType == ElRemove || selected[uint(i)] {
+			continue
+		}
+		for _, p := range el.Indexes {
+			fixed[p] = true
+		}
+	}
+	// supported nodes
+	walkGroups(mm.GetRootGroup(), func(gr groups.Group) {
+		if g, ok := gr.(*groups.NodeSupports); ok {
+			for _, n := range g.Nodes {
+				fixed[int(n)] = true
+			}
+		}
+	})
+	var nodes []int
+	for n := range neighbours {
+		if !fixed[n] {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Ints(nodes)
+	// normals of plates before smoothing
+	normals := map[uint][3]float64{}
+	for _, p := range plates {
+		normals[p] = plateNormal(mm.getPoint3d(p))
+	}
+	// plates without inversion and concave corners
+	valid := func(els []uint) bool {
+		for _, e := range els {
+			ps := mm.getPoint3d(e)
+			for i := range ps {
+				prev, next := ps[(i+len(ps)-1)%len(ps)], ps[(i+1)%len(ps)]
+				if dot(cross(vector(prev, ps[i]), vector(ps[i], next)), normals[e]) <= 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for it := uint(0); it < iterations; it++ {
+		for _, n := range nodes {
+			var c gog.Point3d
+			for p := range neighbours[n] {
+				for i := range c {
+					c[i] += mm.Coords[p].Point3d[i] / float64(len(neighbours[n]))
+				}
+			}
+			if !guarded {
+				mm.Coords[n].Point3d = c
+				continue
+			}
+			old := mm.Coords[n].Point3d
+			angle := minAngle(around[n])
+			mm.Coords[n].Point3d = c
+			if !valid(around[n]) || minAngle(around[n]) < angle {
+				mm.Coords[n].Point3d = old
+			}
+		}
+	}
+	after = minAngle(plates)
+	return
+}
+
+// SplitQuadr4To2Tri3 split quadrilaterals into 2 triangles by shorter
+// diagonal
+func (mm *Model) SplitQuadr4To2Tri3(elements []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("SplitQuadr4To2Tri3: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	if len(elements) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		el := mm.Elements[eid]
+		if el.ElementType != Quadr4 {
+			continue
+		}
+		idx := el.Indexes
+		ps := mm.getPoint3d(eid)
+		if gog.Distance3d(ps[1], ps[3]) < gog.Distance3d(ps[0], ps[2]) {
+			// shift for diagonal from first node
+			idx = []int{idx[1], idx[2], idx[3], idx[0]}
+		}
+		// TODO loads on all elements
+		mm.Elements[eid].ElementType = Triangle3
+		mm.Elements[eid].Indexes = []int{idx[0], idx[1], idx[2]}
+		mm.AddTriangle3ByNodeNumber(uint(idx[0]), uint(idx[2]), uint(idx[3]))
+	}
+}
+
+// SplitQuadr4To4Tri3 split quadrilaterals into 4 triangles by center node
+func (mm *Model) SplitQuadr4To4Tri3(elements []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("SplitQuadr4To4Tri3: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	if len(elements) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		el := mm.Elements[eid]
+		if el.ElementType != Quadr4 {
+			continue
+		}
+		idx := el.Indexes
+		o := centroid(mm.getPoint3d(eid))
+		center := int(mm.AddNode(o[0], o[1], o[2]))
+		// TODO loads on all elements
+		mm.Elements[eid].ElementType = Triangle3
+		mm.Elements[eid].Indexes = []int{idx[0], idx[1], center}
+		for k := 1; k < 4; k++ {
+			mm.AddTriangle3ByNodeNumber(uint(idx[k]), uint(idx[(k+1)%4]), uint(center))
+		}
+	}
+}
+
+// ConvertTri3ToQuadr4 merge pairs of neighbour triangles into
+// flat quadrilaterals with good shape. Triangles without pair are not
+// changed.
+func (mm *Model) ConvertTri3ToQuadr4(elements []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("ConvertTri3ToQuadr4: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	var ids []uint
+	var trs [][3]uint
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		el := mm.Elements[eid]
+		if el.ElementType != Triangle3 {
+			continue
+		}
+		ids = append(ids, eid)
+		trs = append(trs, [3]uint{uint(el.Indexes[0]), uint(el.Indexes[1]), uint(el.Indexes[2])})
+	}
+	if len(trs) < 2 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	quadrs, pairs, _ := pairTriangles(trs, func(n uint) gog.Point3d {
+		return mm.Coords[n].Point3d
+	})
+	var remove []uint
+	for i, q := range quadrs {
+		// TODO loads on all elements
+		eid := ids[pairs[i][0]]
+		mm.Elements[eid].ElementType = Quadr4
+		mm.Elements[eid].Indexes = []int{int(q[0]), int(q[1]), int(q[2]), int(q[3])}
+		remove = append(remove, ids[pairs[i][1]])
+	}
+	mm.Remove(nil, remove)
+}
+
+// splitPolygon return plates of polygon. Triangles is created for
+// triangle source and quadrilaterals with triangles for other.
+func (mm *Model) splitPolygon(poly []int, triangles bool) (plates [][]int) {
+	point := func(n int) gog.Point3d { return mm.Coords[n].Point3d }
+	switch {
+	case len(poly) == 3:
+		return [][]int{poly}
+	case len(poly) == 4 && !triangles:
+		return [][]int{poly}
+	case len(poly) == 4:
+		// shorter diagonal
+		if gog.Distance3d(point(poly[1]), point(poly[3])) < gog.Distance3d(point(poly[0]), point(poly[2])) {
+			poly = []int{poly[1], poly[2], poly[3], poly[0]}
+		}
+		return [][]int{{poly[0], poly[1], poly[2]}, {poly[0], poly[2], poly[3]}}
+	}
+	// pentagon
+	best, skew := 0, math.Inf(1)
+	for i := range poly {
+		var ps [4]gog.Point3d
+		for k := range ps {
+			ps[k] = point(poly[(i+2+k)%len(poly)])
+		}
+		if quadrNegativeCorners(ps) != 0 {
+			continue
+		}
+		s := 0.0
+		for _, angle := range plateAngles(ps[:]) {
+			s = math.Max(s, math.Abs(angle-90))
+		}
+		if s < skew {
+			best, skew = i, s
+		}
+	}
+	if triangles || math.IsInf(skew, 1) {
+		for i := 1; i+1 < len(poly); i++ {
+			plates = append(plates, []int{poly[0], poly[i], poly[i+1]})
+		}
+		return
+	}
+	n := len(poly)
+	return [][]int{
+		{poly[best], poly[(best+1)%n], poly[(best+2)%n]},
+		{poly[(best+2)%n], poly[(best+3)%n], poly[(best+4)%n], poly[best]},
+	}
+}
+
+// SplitByPlane split lines, triangles and quadrilaterals crossed by
+// plane of 3 points. New nodes are created on plane and all elements with
+// split edges are split too for conforming mesh. If section is true,
+// then lines along cut of plates are created.
+func (mm *Model) SplitByPlane(plane [3]gog.Point3d, elements []uint, section bool) (lines []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("SplitByPlane: not valid elements id: %v", s)
+		return
+	}
+	n := cross(vector(plane[0], plane[1]), vector(plane[0], plane[2]))
+	if norm(n) < gog.Eps3D {
+		logger.Printf("SplitByPlane: not valid plane: %v", plane)
+		return
+	}
+	// actions
+	if len(elements) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	l := norm(n)
+	for i := range n {
+		n[i] /= l
+	}
+	distance := func(node int) float64 {
+		return dot(n, vector(plane[0], mm.Coords[node].Point3d))
+	}
+	side := func(node int) int {
+		d := distance(node)
+		switch {
+		case gog.Eps3D < d:
+			return 1
+		case d < -gog.Eps3D:
+			return -1
+		}
+		return 0
+	}
+	// nodes on crossed edges
+	cuts := map[[2]int]int{}
+	edge := func(a, b int) [2]int {
+		if b < a {
+			a, b = b, a
+		}
+		return [2]int{a, b}
+	}
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		idx := mm.Elements[eid].Indexes
+		if t := mm.Elements[eid].ElementType; t != Line2 && t != Triangle3 && t != Quadr4 {
+			continue
+		}
+		for k := range idx {
+			a, b := idx[k], idx[(k+1)%len(idx)]
+			if side(a)*side(b) != -1 {
+				continue
+			}
+			if _, ok := cuts[edge(a, b)]; ok {
+				continue
+			}
+			da, db := distance(a), distance(b)
+			p := gog.PointLineRatio3d(mm.Coords[a].Point3d, mm.Coords[b].Point3d, da/(da-db))
+			cuts[edge(a, b)] = int(mm.AddNode(p[0], p[1], p[2]))
+		}
+	}
+	// split elements with crossed edges
+	selected := map[uint]bool{}
+	for _, eid := range elements {
+		selected[eid] = true
+	}
+	for i := range mm.Elements {
+		el := mm.Elements[i]
+		if t := el.ElementType; t != Line2 && t != Triangle3 && t != Quadr4 {
+			continue
+		}
+		// polygon with nodes on plane
+		var poly []int
+		crossed := false
+		for k := range el.Indexes {
+			a, b := el.Indexes[k], el.Indexes[(k+1)%len(el.Indexes)]
+			poly = append(poly, a)
+			if el.ElementType == Line2 && k == 1 {
+				break
+			}
+			if c, ok := cuts[edge(a, b)]; ok {
+				poly = append(poly, c)
+				crossed = true
+			}
+		}
+		var on []int
+		for _, p := range poly {
+			if side(p) == 0 {
+				on = append(on, p)
+			}
+		}
+		if !crossed && el.ElementType == Quadr4 && len(on) == 2 {
+			// plane by diagonal of quadrilateral
+			for k := 0; k < 2; k++ {
+				if side(poly[k]) == 0 && side(poly[k+2]) == 0 &&
+					side(poly[k+1])*side(poly[(k+3)%4]) == -1 {
+					crossed = true
+				}
+			}
+		}
+		if section && selected[uint(i)] && el.ElementType != Line2 && len(on) == 2 {
+			// id 0 is returned on fail
+			id := mm.AddLineByNodeNumber(uint(on[0]), uint(on[1]))
+			if l := mm.Elements[id]; l.ElementType == Line2 &&
+				edge(l.Indexes[0], l.Indexes[1]) == edge(on[0], on[1]) {
+				lines = append(lines, id)
+			}
+		}
+		if !crossed {
+			continue
+		}
+		if el.ElementType == Line2 {
+			mm.Elements[i].Indexes = []int{poly[0], poly[1]}
+			mm.AddLineByNodeNumber(uint(poly[1]), uint(poly[2]))
+			continue
+		}
+		// plates on both sides of plane
+		var plates [][]int
+		for _, s := range []int{1, -1} {
+			var part []int
+			for _, p := range poly {
+				if side(p) != -s {
+					part = append(part, p)
+				}
+			}
+			if 3 <= len(part) {
+				plates = append(plates, mm.splitPolygon(part, el.ElementType == Triangle3)...)
+			}
+		}
+		// TODO loads on all elements
+		for k, p := range plates {
+			if k == 0 {
+				mm.Elements[i].ElementType = Triangle3
+				if len(p) == 4 {
+					mm.Elements[i].ElementType = Quadr4
+				}
+				mm.Elements[i].Indexes = p
+				continue
+			}
+			if len(p) == 3 {
+				mm.AddTriangle3ByNodeNumber(uint(p[0]), uint(p[1]), uint(p[2]))
+				continue
+			}
+			mm.AddQuadr4ByNodeNumber(uint(p[0]), uint(p[1]), uint(p[2]), uint(p[3]))
+		}
+	}
+	// section lines on common edges of elements
+	if 0 < len(lines) {
+		lines = uniqUint(lines)
+	}
+	return
+}
@@ -0,0 +1,523 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// square add closed loop of lines for square in plane XOY with center
+// in point (x, y) and size a with n divisions on each side
+func square(mm *Model, x, y, a float64, n int) (lines []uint) {
+	var ids []uint
+	for side := 0; side < 4; side++ {
+		for i := 0; i < n; i++ {
+			t := a * float64(i) / float64(n)
+			var px, py float64
+			switch side {
+			case 0:
+				px, py = t, 0
+			case 1:
+				px, py = a, t
+			case 2:
+				px, py = a-t, a
+			case 3:
+				px, py = 0, a-t
+			}
+			ids = append(ids, mm.AddNode(x-a/2+px, y-a/2+py, 0))
+		}
+	}
+	for i := range ids {
+		lines = append(lines, mm.AddLineByNodeNumber(ids[i], ids[(i+1)%len(ids)]))
+	}
+	return
+}
+
+func TestTriangulation(t *testing.T) {
+	var mm Model
+	lines := square(&mm, 0, 0, 4, 4)
+	lines = append(lines, square(&mm, 0, 0, 1, 1)...)
+	// inner node
+	n := mm.AddNode(1.5, 1.5, 0)
+	trs := mm.Triangulation([]uint{n}, lines)
+	if len(trs) == 0 {
+		t.Fatalf("triangulation is empty")
+	}
+	area := mm.TotalArea(trs)
+	if math.Abs(area-15) > 1e-9 {
+		t.Errorf("not valid area: %v", area)
+	}
+	// all triangles have normal of plane
+	var sign float64
+	for _, tr := range trs {
+		ps := mm.getPoint3d(tr)
+		c := cross(vector(ps[0], ps[1]), vector(ps[0], ps[2]))
+		if sign == 0 {
+			sign = c[2]
+		}
+		if c[2]*sign <= 0 {
+			t.Errorf("not valid normal of triangle %d", tr)
+		}
+	}
+	if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 || len(fs[CollinearTriangle]) != 0 {
+		t.Errorf("not valid triangles: %v", fs)
+	}
+}
+
+func TestMeshArea(t *testing.T) {
+	for _, tc := range []struct {
+		size  float64
+		quadr bool
+	}{
+		{0.5, false},
+		{0.5, true},
+		{0.3, false},
+		{0.3, true},
+	} {
+		quadr := tc.quadr
+		t.Run(fmt.Sprintf("%v", tc), func(t *testing.T) {
+			var mm Model
+			lines := square(&mm, 0, 0, 4, 1)
+			lines = append(lines, square(&mm, 0.5, 0.5, 1, 1)...)
+			boundary := len(mm.Coords)
+			els := mm.MeshArea(lines, tc.size, quadr)
+			if len(els) == 0 {
+				t.Fatalf("mesh is empty")
+			}
+			area := mm.TotalArea(els)
+			if math.Abs(area-15) > 1e-9 {
+				t.Errorf("not valid area: %v", area)
+			}
+			var amount [2]int
+			for _, el := range els {
+				switch mm.Elements[el].ElementType {
+				case Triangle3:
+					amount[0]++
+				case Quadr4:
+					amount[1]++
+				}
+			}
+			if quadr && amount[1] <= amount[0] {
+				t.Errorf("not enough quadrilaterals: %v", amount)
+			}
+			if !quadr && amount[1] != 0 {
+				t.Errorf("quadrilaterals in triangle mesh: %v", amount)
+			}
+			// boundary nodes are used by mesh
+			used := map[int]bool{}
+			for _, el := range els {
+				for _, p := range mm.Elements[el].Indexes {
+					used[p] = true
+				}
+			}
+			for n := 0; n < boundary; n++ {
+				if !used[n] {
+					t.Errorf("boundary node %d is not in mesh", n)
+				}
+			}
+			// mesh is conforming with boundary lines
+			if fs := mm.CheckAll(); 0 < len(fs) {
+				for _, f := range fs {
+					if f.Severity != SeverityInfo {
+						t.Errorf("%v", f)
+					}
+				}
+			}
+			if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 {
+				t.Errorf("not valid plates: %v", fs)
+			}
+		})
+	}
+}
+
+// grid add quadrilaterals on grid n x n with size 1 in plane XOY
+func grid(mm *Model, n int) (quadrs []uint) {
+	ids := make([][]uint, n+1)
+	for i := range ids {
+		for j := 0; j <= n; j++ {
+			ids[i] = append(ids[i], mm.AddNode(float64(i), float64(j), 0))
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			id, _ := mm.AddQuadr4ByNodeNumber(ids[i][j], ids[i+1][j], ids[i+1][j+1], ids[i][j+1])
+			quadrs = append(quadrs, id)
+		}
+	}
+	return
+}
+
+func TestSmoothMesh(t *testing.T) {
+	for _, guarded := range []bool{false, true} {
+		t.Run(fmt.Sprintf("%v", guarded), func(t *testing.T) {
+			var mm Model
+			qs := grid(&mm, 4)
+			// move inner nodes
+			for i, c := range mm.Coords {
+				x, y := c.Point3d[0], c.Point3d[1]
+				if 0 < x && x < 4 && 0 < y && y < 4 {
+					mm.Coords[i].Point3d[0] += 0.3 * math.Sin(float64(3*i))
+					mm.Coords[i].Point3d[1] += 0.3 * math.Cos(float64(5*i))
+				}
+			}
+			// supported node
+			supported := uint(6)
+			meta := &mm.Groups.meta
+			meta.Groups = append(meta.Groups,
+				&groups.NodeSupports{Nodes: []uint{supported}})
+			// line on node
+			line := mm.AddLineByNodeNumber(12, 13)
+			coords := append([]Coordinate(nil), mm.Coords...)
+
+			before, after := mm.SmoothMesh(qs, 20, guarded)
+			if after <= before {
+				t.Errorf("quality is not improved: %v, %v", before, after)
+			}
+			for _, n := range append([]int{int(supported)}, mm.Elements[line].Indexes...) {
+				if mm.Coords[n] != coords[n] {
+					t.Errorf("fixed node %d is moved", n)
+				}
+			}
+			for i, c := range mm.Coords {
+				x, y := coords[i].Point3d[0], coords[i].Point3d[1]
+				if (x == 0 || x == 4 || y == 0 || y == 4) && c != coords[i] {
+					t.Errorf("boundary node %d is moved", i)
+				}
+			}
+			if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 ||
+				len(fs[ConcaveQuadr]) != 0 || len(fs[BowTieQuadr]) != 0 {
+				t.Errorf("not valid plates: %v", fs)
+			}
+		})
+	}
+}
+
+func TestSplitTo4(t *testing.T) {
+	amount := func(mm *Model) (amount [lastElement]int) {
+		for _, el := range mm.Elements {
+			amount[el.ElementType]++
+		}
+		return
+	}
+	check := func(t *testing.T, mm *Model, area float64) {
+		t.Helper()
+		var all []uint
+		for i := range mm.Elements {
+			all = append(all, uint(i))
+		}
+		if a := mm.TotalArea(all); math.Abs(a-area) > 1e-9 {
+			t.Errorf("not valid area: %v", a)
+		}
+		for _, f := range mm.CheckAll() {
+			if f.Severity != SeverityInfo {
+				t.Errorf("%v", f)
+			}
+		}
+		if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	}
+	t.Run("triangles", func(t *testing.T) {
+		var mm Model
+		var (
+			a = mm.AddNode(0, 0, 0)
+			b = mm.AddNode(1, 0, 0)
+			c = mm.AddNode(1, 1, 0)
+			d = mm.AddNode(0, 1, 0)
+		)
+		t1, _ := mm.AddTriangle3ByNodeNumber(a, b, c)
+		t2, _ := mm.AddTriangle3ByNodeNumber(a, c, d)
+		mm.AddLineByNodeNumber(a, c)
+		mm.SplitTri3To4Tri3([]uint{t1, t2})
+		if n := amount(&mm); n[Triangle3] != 8 || n[Line2] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 1)
+	})
+	t.Run("quadrilaterals", func(t *testing.T) {
+		var mm Model
+		qs := grid(&mm, 2)
+		mm.AddLineByNodeNumber(0, 3)
+		mm.SplitQuadr4To4Quadr4(qs)
+		if n := amount(&mm); n[Quadr4] != 16 || n[Line2] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		if len(mm.Coords) != 25 {
+			t.Errorf("middle nodes is not shared: %d", len(mm.Coords))
+		}
+		check(t, &mm, 4)
+	})
+	// edges of plates with one plate are on border of grid only
+	conforming := func(t *testing.T, mm *Model, size float64) {
+		t.Helper()
+		edges := map[[2]int]int{}
+		for _, el := range mm.Elements {
+			if el.ElementType != Triangle3 && el.ElementType != Quadr4 {
+				continue
+			}
+			for k, a := range el.Indexes {
+				b := el.Indexes[(k+1)%len(el.Indexes)]
+				if b < a {
+					a, b = b, a
+				}
+				edges[[2]int{a, b}]++
+			}
+		}
+		border := func(n int) bool {
+			for _, v := range mm.Coords[n].Point3d[:2] {
+				if v == 0 || v == size {
+					return true
+				}
+			}
+			return false
+		}
+		for e, n := range edges {
+			if n == 1 && !(border(e[0]) && border(e[1])) {
+				t.Errorf("hanging node on edge: %v", e)
+			}
+		}
+	}
+	t.Run("not flat quadrilateral", func(t *testing.T) {
+		var mm Model
+		var (
+			a = mm.AddNode(0, 0, 0)
+			b = mm.AddNode(1, 0, 0)
+			c = mm.AddNode(1, 1, 0.2)
+			d = mm.AddNode(0, 1, 0)
+		)
+		mm.Elements = append(mm.Elements, Element{
+			ElementType: Quadr4,
+			Indexes:     []int{int(a), int(b), int(c), int(d)},
+		})
+		area := mm.TotalArea([]uint{0})
+		mm.SplitQuadr4To4Quadr4([]uint{0})
+		var all []uint
+		for i := range mm.Elements {
+			all = append(all, uint(i))
+		}
+		if a := mm.TotalArea(all); math.Abs(a-area) > 0.01*area {
+			t.Errorf("not valid area: %v != %v", a, area)
+		}
+	})
+	t.Run("part of triangles", func(t *testing.T) {
+		var mm Model
+		mm.SplitQuadr4To2Tri3(grid(&mm, 2))
+		mm.SplitTri3To4Tri3([]uint{0})
+		conforming(t, &mm, 2)
+		check(t, &mm, 4)
+	})
+	t.Run("part of quadrilaterals", func(t *testing.T) {
+		var mm Model
+		qs := grid(&mm, 2)
+		mm.AddLineByNodeNumber(0, 3)
+		mm.SplitQuadr4To4Quadr4(qs[:1])
+		if n := amount(&mm); n[Quadr4] != 5 || n[Triangle3] != 6 || n[Line2] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		conforming(t, &mm, 2)
+		check(t, &mm, 4)
+	})
+}
+
+func TestConvertPlates(t *testing.T) {
+	active := func(mm *Model, e ElType) (ids []uint) {
+		for i, el := range mm.Elements {
+			if el.ElementType == e {
+				ids = append(ids, uint(i))
+			}
+		}
+		return
+	}
+	t.Run("shorter diagonal", func(t *testing.T) {
+		var mm Model
+		var (
+			a = mm.AddNode(0, 0, 0)
+			b = mm.AddNode(2, 0, 0)
+			c = mm.AddNode(3, 1, 0)
+			d = mm.AddNode(1, 1, 0)
+		)
+		q, _ := mm.AddQuadr4ByNodeNumber(a, b, c, d)
+		mm.SplitQuadr4To2Tri3([]uint{q})
+		trs := active(&mm, Triangle3)
+		if len(trs) != 2 {
+			t.Fatalf("not valid amount of triangles: %v", trs)
+		}
+		for _, tr := range trs {
+			has := map[int]bool{}
+			for _, p := range mm.Elements[tr].Indexes {
+				has[p] = true
+			}
+			if !has[int(b)] || !has[int(d)] {
+				t.Errorf("triangle is not on shorter diagonal: %v", mm.Elements[tr].Indexes)
+			}
+		}
+		if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	})
+	t.Run("center node", func(t *testing.T) {
+		var mm Model
+		qs := grid(&mm, 1)
+		mm.SplitQuadr4To4Tri3(qs)
+		trs := active(&mm, Triangle3)
+		if len(trs) != 4 || len(mm.Coords) != 5 {
+			t.Fatalf("not valid mesh: %v", trs)
+		}
+		if a := mm.TotalArea(trs); math.Abs(a-1) > 1e-9 {
+			t.Errorf("not valid area: %v", a)
+		}
+		if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	})
+	t.Run("pairing", func(t *testing.T) {
+		var mm Model
+		qs := grid(&mm, 3)
+		mm.SplitQuadr4To2Tri3(qs)
+		if trs := active(&mm, Triangle3); len(trs) != 18 {
+			t.Fatalf("not valid amount of triangles: %d", len(trs))
+		}
+		mm.ConvertTri3ToQuadr4(active(&mm, Triangle3))
+		if trs := active(&mm, Triangle3); len(trs) != 0 {
+			t.Errorf("not paired triangles: %v", trs)
+		}
+		qs = active(&mm, Quadr4)
+		if len(qs) != 9 {
+			t.Errorf("not valid amount of quadrilaterals: %d", len(qs))
+		}
+		if a := mm.TotalArea(qs); math.Abs(a-9) > 1e-9 {
+			t.Errorf("not valid area: %v", a)
+		}
+		if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 ||
+			len(fs[ConcaveQuadr]) != 0 || len(fs[BowTieQuadr]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	})
+	t.Run("folded surface", func(t *testing.T) {
+		var mm Model
+		// triangles with angle 50 degrees between planes
+		angle := 50 * math.Pi / 180
+		var (
+			a = mm.AddNode(0, 0, 0)
+			b = mm.AddNode(1, 0, 0)
+			c = mm.AddNode(1, 1, 0)
+			d = mm.AddNode(0.5-0.5*math.Cos(angle), 0.5+0.5*math.Cos(angle), 0.5*math.Sqrt2*math.Sin(angle))
+		)
+		t1, _ := mm.AddTriangle3ByNodeNumber(a, b, c)
+		t2, _ := mm.AddTriangle3ByNodeNumber(a, c, d)
+		mm.ConvertTri3ToQuadr4([]uint{t1, t2})
+		if trs := active(&mm, Triangle3); len(trs) != 2 {
+			t.Errorf("folded triangles are paired: %v", trs)
+		}
+		if fs := mm.CheckPlates(defaultWarping); len(fs[WarpedQuadr]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	})
+}
+
+func TestSplitByPlane(t *testing.T) {
+	all := func(mm *Model) (ids []uint) {
+		for i, el := range mm.Elements {
+			if el.ElementType != ElRemove {
+				ids = append(ids, uint(i))
+			}
+		}
+		return
+	}
+	amount := func(mm *Model) (amount [lastElement]int) {
+		for _, el := range mm.Elements {
+			amount[el.ElementType]++
+		}
+		return
+	}
+	check := func(t *testing.T, mm *Model, area float64) {
+		t.Helper()
+		if a := mm.TotalArea(all(mm)); math.Abs(a-area) > 1e-9 {
+			t.Errorf("not valid area: %v", a)
+		}
+		for _, f := range mm.CheckAll() {
+			if f.Severity != SeverityInfo {
+				t.Errorf("%v", f)
+			}
+		}
+		if fs := mm.CheckPlates(5); len(fs[InvertedNormal]) != 0 ||
+			len(fs[ConcaveQuadr]) != 0 || len(fs[BowTieQuadr]) != 0 {
+			t.Errorf("not valid plates: %v", fs)
+		}
+	}
+	t.Run("quadrilaterals", func(t *testing.T) {
+		var mm Model
+		grid(&mm, 2)
+		mm.AddLineByNodeNumber(0, 6)
+		plane := [3]gog.Point3d{{0.5, 0, 0}, {0.5, 1, 0}, {0.5, 0, 1}}
+		lines := mm.SplitByPlane(plane, all(&mm), true)
+		if len(lines) != 2 {
+			t.Errorf("not valid section lines: %v", lines)
+		}
+		for _, l := range lines {
+			for _, p := range mm.Elements[l].Indexes {
+				if x := mm.Coords[p].Point3d[0]; math.Abs(x-0.5) > 1e-9 {
+					t.Errorf("node %d is not on plane", p)
+				}
+			}
+		}
+		if n := amount(&mm); n[Quadr4] != 6 || n[Line2] != 4 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 4)
+	})
+	t.Run("common edges", func(t *testing.T) {
+		var mm Model
+		grid(&mm, 2)
+		plane := [3]gog.Point3d{{1, 0, 0}, {1, 1, 0}, {1, 0, 1}}
+		lines := mm.SplitByPlane(plane, all(&mm), true)
+		if len(lines) != 2 || lines[0] == lines[1] {
+			t.Errorf("not valid section lines: %v", lines)
+		}
+		if n := amount(&mm); n[Quadr4] != 4 || n[Line2] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 4)
+	})
+	t.Run("corner", func(t *testing.T) {
+		var mm Model
+		grid(&mm, 1)
+		plane := [3]gog.Point3d{{0.5, 0, 0}, {0, 0.5, 0}, {0, 0.5, 1}}
+		mm.SplitByPlane(plane, all(&mm), false)
+		if n := amount(&mm); n[Quadr4] != 1 || n[Triangle3] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 1)
+	})
+	t.Run("diagonal", func(t *testing.T) {
+		var mm Model
+		grid(&mm, 1)
+		plane := [3]gog.Point3d{{0, 0, 0}, {1, 1, 0}, {0, 0, 1}}
+		lines := mm.SplitByPlane(plane, all(&mm), true)
+		if len(lines) != 1 {
+			t.Fatalf("not valid section lines: %v", lines)
+		}
+		for _, p := range mm.Elements[lines[0]].Indexes {
+			if ps := mm.Coords[p].Point3d; math.Abs(ps[0]-ps[1]) > 1e-9 {
+				t.Errorf("node %d is not on plane", p)
+			}
+		}
+		if n := amount(&mm); n[Quadr4] != 0 || n[Triangle3] != 2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 1)
+	})
+	t.Run("triangles", func(t *testing.T) {
+		var mm Model
+		mm.SplitQuadr4To2Tri3(grid(&mm, 2))
+		plane := [3]gog.Point3d{{0.3, 0, 0}, {0.3, 1, 0}, {0.3, 0, 1}}
+		mm.SplitByPlane(plane, all(&mm), false)
+		if n := amount(&mm); n[Quadr4] != 0 || n[Triangle3] != 8+4*2 {
+			t.Errorf("not valid amount of elements: %v", n)
+		}
+		check(t, &mm, 4)
+	})
+}
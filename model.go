@@ -33,6 +33,7 @@ const (
 	Line2     ElType = iota + 1 // 1
 	Triangle3                   // 2
 	Quadr4                      // 3
+	Hexa8                       // 4
 	lastElement
 	ElRemove = math.MaxUint8 // 255
 )
@@ -45,6 +46,8 @@ func (e ElType) String() string {
 		return "Triangle with 3 points"
 	case Quadr4:
 		return "Quard with 4 points"
+	case Hexa8:
+		return "Hexa with 8 points"
 	}
 	return "Undefined type element"
 }
@@ -57,6 +60,8 @@ func (e ElType) getSelect() viewState {
 		return selectTriangles
 	case Quadr4:
 		return selectQuadrs
+	case Hexa8:
+		return selectHexas
 	}
 	panic(fmt.Errorf("undefined getSelect: %v", e))
 }
@@ -80,6 +85,16 @@ func (e ElType) getSelect() viewState {
 //	       o======o
 //	ElType : 3
 //	Indexes: 4 (amount indexes of coordinates)
+//
+//	Hexa8     o======o
+//	         /|     /|
+//	        o======o |
+//	        | o====|=o
+//	        |/     |/
+//	        o======o
+//	ElType : 4
+//	Indexes: 8 (amount indexes of coordinates), first 4 indexes is
+//	bottom face and last 4 indexes is top face
 type Element struct {
 	object3d
 	ElementType ElType
@@ -94,6 +109,7 @@ var valids = []struct {
 	{Line2, 2, AddLinesLC},
 	{Triangle3, 3, AddTrianglesLC},
 	{Quadr4, 4, AddQuardsLC},
+	{Hexa8, 8, AddHexasLC},
 	{e: ElRemove, amount: 0},
 }
 
@@ -150,6 +166,7 @@ type Model struct {
 		meta groups.Meta
 	}
 	filename string
+	results  results
 }
 
 func (mm Model) getPoint3d(index uint) (ps []gog.Point3d) {
@@ -170,9 +187,28 @@ func (mm Model) getPoint3d(index uint) (ps []gog.Point3d) {
 
 func (mm *Model) Check() error {
 	et := etree.New("check model")
+	for _, f := range mm.validity() {
+		_ = et.Add(fmt.Errorf("%s", f.Message))
+	}
+	if et.IsError() {
+		return et
+	}
+	return nil
+}
+
+// validity return errors of model structure
+func (mm *Model) validity() (findings []Finding) {
+	add := func(nodes, elements []uint, format string, a ...any) {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf(format, a...),
+			Nodes:    nodes,
+			Elements: elements,
+		})
+	}
 	for i, c := range mm.Coords {
 		if err := c.Check(); err != nil {
-			_ = et.Add(fmt.Errorf("Coordinate: %d\n%v", i, err))
+			add([]uint{uint(i)}, nil, "Coordinate: %d\n%v", i, err)
 		}
 	}
 	for i := range mm.Coords {
@@ -180,39 +216,37 @@ func (mm *Model) Check() error {
 			if mm.isValidValue(v) {
 				continue
 			}
-			_ = et.Add(fmt.Errorf("Coords: %d\nNot valid value", i))
+			add([]uint{uint(i)}, nil, "Coords: %d\nNot valid value", i)
 		}
 	}
 	for i, el := range mm.Elements {
 		if err := el.Check(); err != nil {
-			_ = et.Add(fmt.Errorf("Element type `%d`: %d\n%v", el.ElementType, i, err))
+			add(nil, []uint{uint(i)}, "Element type `%d`: %d\n%v", el.ElementType, i, err)
 		}
 		for _, p := range el.Indexes {
 			if p < 0 {
-				_ = et.Add(fmt.Errorf("Element: %d\nCoordinate index is negative", i))
+				add(nil, []uint{uint(i)}, "Element: %d\nCoordinate index is negative", i)
 			}
 			if len(mm.Coords) <= p {
-				_ = et.Add(fmt.Errorf("Element: %d\nCoordinate index is too big", i))
+				add(nil, []uint{uint(i)}, "Element: %d\nCoordinate index is too big", i)
 			}
 		}
-		for i := range el.Indexes {
+		for k := range el.Indexes {
 			for j := range el.Indexes {
-				if i <= j {
+				if k <= j {
 					continue
 				}
-				if el.Indexes[i] == el.Indexes[j] {
-					_ = et.Add(fmt.Errorf("Element: same indexes %v", el.Indexes))
+				if el.Indexes[k] == el.Indexes[j] {
+					add(nil, []uint{uint(i)}, "Element: same indexes %v", el.Indexes)
 				}
 			}
 		}
 	}
-	if et.IsError() {
-		return et
+	if err := groups.CheckRigidLinks(mm.GetRootGroup()); err != nil {
+		add(nil, nil, "%v", err)
 	}
 	// TODO check - not same coordiantes
-	// TODO check - Triangle3 , Quadr4 not on one line
-	// TODO check - Quadr4 on one plane
-	return nil
+	return
 }
 
 // TODO
@@ -412,6 +446,12 @@ func (mm *Model) AddModel(m Model) {
 				uint(newID[el.Indexes[2]]),
 				uint(newID[el.Indexes[3]]),
 			)
+		case Hexa8:
+			var ns [8]uint
+			for i := range ns {
+				ns[i] = uint(newID[el.Indexes[i]])
+			}
+			mm.AddHexa8ByNodeNumber(ns)
 		default:
 			logger.Printf("AddModel: not implemented %v", el)
 		}
@@ -605,19 +645,9 @@ func (mm *Model) AddQuadr4ByNodeNumber(n1, n2, n3, n4 uint) (id uint, ok bool) {
 		return
 	}
 	// check all points on one plane
-	{
-		A, B, C, D := gog.Plane(
-			mm.Coords[int(n1)].Point3d,
-			mm.Coords[int(n2)].Point3d,
-			mm.Coords[int(n3)].Point3d,
-		)
-		if !gog.PointOnPlane3d(
-			A, B, C, D,
-			mm.Coords[int(n4)].Point3d,
-		) {
-			logger.Printf("AddQuadr4ByNodeNumber: not on one plane")
-			return
-		}
+	if !mm.isFlat([4]uint{n1, n2, n3, n4}) {
+		logger.Printf("AddQuadr4ByNodeNumber: not on one plane")
+		return
 	}
 	// check that triangle is not exist
 	nis := [][4]uint{
@@ -656,18 +686,24 @@ func (mm *Model) AddQuadr4ByNodeNumber(n1, n2, n3, n4 uint) (id uint, ok bool) {
 	}
 	// avoid insection with it-self
 	{
-		_, _, intersection1 := gog.LineLine3d(
+		// intersection of lines only inside of both sides
+		inside := func(ratioA, ratioB float64, intersect bool) bool {
+			return intersect &&
+				0 < ratioA && ratioA < 1 &&
+				0 < ratioB && ratioB < 1
+		}
+		intersection1 := inside(gog.LineLine3d(
 			mm.Coords[int(n1)].Point3d,
 			mm.Coords[int(n2)].Point3d,
 			mm.Coords[int(n3)].Point3d,
 			mm.Coords[int(n4)].Point3d,
-		)
-		_, _, intersection2 := gog.LineLine3d(
+		))
+		intersection2 := inside(gog.LineLine3d(
 			mm.Coords[int(n2)].Point3d,
 			mm.Coords[int(n3)].Point3d,
 			mm.Coords[int(n4)].Point3d,
 			mm.Coords[int(n1)].Point3d,
-		)
+		))
 		if intersection1 || intersection2 {
 			n3, n4 = n4, n3 // swap nodes
 		}
@@ -680,6 +716,95 @@ func (mm *Model) AddQuadr4ByNodeNumber(n1, n2, n3, n4 uint) (id uint, ok bool) {
 	return uint(len(mm.Elements) - 1), true
 }
 
+// hexaFaces is faces of Hexa8 with outside normals
+var hexaFaces = [6][4]int{
+	{0, 3, 2, 1}, {4, 5, 6, 7},
+	{0, 1, 5, 4}, {1, 2, 6, 5},
+	{2, 3, 7, 6}, {3, 0, 4, 7},
+}
+
+// hexaVolume return volume of hexahedron by faces. Volume is negative
+// for hexahedron with inside normals of faces.
+func hexaVolume(ps []gog.Point3d) (volume float64) {
+	for _, face := range hexaFaces {
+		var fs []gog.Point3d
+		for _, k := range face {
+			fs = append(fs, ps[k])
+		}
+		var area [3]float64
+		for i := range fs {
+			c := cross(fs[i], fs[(i+1)%len(fs)])
+			for k := range area {
+				area[k] += c[k] / 2
+			}
+		}
+		volume += dot(centroid(fs), area) / 3
+	}
+	return
+}
+
+// outsideHexa8 swap bottom and top faces of hexahedron with inside
+// normals of faces
+func (mm *Model) outsideHexa8(indexes []int) {
+	var ps []gog.Point3d
+	for _, p := range indexes {
+		ps = append(ps, mm.Coords[p].Point3d)
+	}
+	if 0 <= hexaVolume(ps) {
+		return
+	}
+	for k := 0; k < 4; k++ {
+		indexes[k], indexes[k+4] = indexes[k+4], indexes[k]
+	}
+}
+
+// AddHexa8ByNodeNumber add hexahedron by 8 nodes. First 4 nodes is
+// bottom face and last 4 nodes is top face in same order. Faces are
+// swapped for hexahedron with inside normals, for example after mirror.
+func (mm *Model) AddHexa8ByNodeNumber(ns [8]uint) (id uint, ok bool) {
+	// check
+	if s := ns[:]; !mm.isValidNodeId(s) {
+		logger.Printf("AddHexa8ByNodeNumber: not valid node id: %v", s)
+		return
+	}
+	// actions
+	key := append([]uint(nil), ns[:]...)
+	key = uniqUint(key)
+	if len(key) != len(ns) {
+		logger.Printf("AddHexa8ByNodeNumber: not unique nodes")
+		return
+	}
+	// check that hexa is not exist
+	for i, el := range mm.Elements {
+		if el.ElementType != Hexa8 {
+			continue
+		}
+		var other []uint
+		for _, p := range el.Indexes {
+			other = append(other, uint(p))
+		}
+		other = uniqUint(other)
+		same := true
+		for k := range key {
+			if key[k] != other[k] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return uint(i), true
+		}
+	}
+	// append
+	el := Element{ElementType: Hexa8}
+	for _, n := range ns {
+		el.Indexes = append(el.Indexes, int(n))
+	}
+	mm.outsideHexa8(el.Indexes)
+	mm.Elements = append(mm.Elements, el)
+	return uint(len(mm.Elements) - 1), true
+}
+
 func (mm *Model) AddConvexLines(nodes, elements []uint) {
 	// check
 	if s := nodes; !mm.isValidNodeId(nodes) {
@@ -855,6 +980,53 @@ func (mm *Model) Remove(nodes, elements []uint) {
 		mm.Elements[p].ElementType = ElRemove
 		mm.Elements[p].Indexes = nil
 	}
+	mm.removeFromGroups()
+}
+
+// copyGroups add elements to all groups of element
+func (mm *Model) copyGroups(element uint, elements []uint) {
+	mm.GetRootGroup().Update(func(_, els *[]uint) {
+		if els == nil {
+			return
+		}
+		has := map[uint]bool{}
+		for _, e := range *els {
+			has[e] = true
+		}
+		if !has[element] {
+			return
+		}
+		for _, e := range elements {
+			if !has[e] {
+				*els = append(*els, e)
+				has[e] = true
+			}
+		}
+	})
+}
+
+// removeFromGroups remove removed nodes and elements from groups
+func (mm *Model) removeFromGroups() {
+	mm.GetRootGroup().Update(func(nodes, elements *[]uint) {
+		if nodes != nil {
+			var ns []uint
+			for _, n := range *nodes {
+				if n < uint(len(mm.Coords)) && !mm.Coords[n].Removed {
+					ns = append(ns, n)
+				}
+			}
+			*nodes = ns
+		}
+		if elements != nil {
+			var els []uint
+			for _, e := range *elements {
+				if e < uint(len(mm.Elements)) && mm.Elements[e].ElementType != ElRemove {
+					els = append(els, e)
+				}
+			}
+			*elements = els
+		}
+	})
 }
 
 // TODO remove
@@ -1005,7 +1177,7 @@ func (mm *Model) InvertSelect(nodes bool, elements []bool) {
 		}
 	}
 	for el := Line2; el < lastElement; el++ {
-		if !elements[el] {
+		if len(elements) <= int(el) || !elements[el] {
 			continue
 		}
 		for i := range mm.Elements {
@@ -1053,7 +1225,7 @@ func (mm *Model) SelectElementsOnPlane(xoy, yoz, xoz bool, elements []bool) {
 		return
 	}
 	for etype := Line2; etype < lastElement; etype++ {
-		if !elements[etype] {
+		if len(elements) <= int(etype) || !elements[etype] {
 			continue
 		}
 		// check each elements type
@@ -1358,7 +1530,7 @@ func (mm *Model) SelectAll(nodes bool, elements []bool) {
 		}
 	}
 	for el := Line2; el < lastElement; el++ {
-		if !elements[el] {
+		if len(elements) <= int(el) || !elements[el] {
 			continue
 		}
 		for i := range mm.Elements {
@@ -2175,6 +2347,178 @@ func (mm *Model) SplitTri3To3Tri3(elements []uint) {
 	}
 }
 
+// middleNodes return shared middle nodes on edges of elements. Lines on
+// the same edges are split by middle nodes and other triangles and
+// quadrilaterals on the same edges are split by transition triangles
+// for conforming mesh.
+func (mm *Model) middleNodes(elements []uint) (middle func(a, b int) int) {
+	mids := map[[2]int]int{}
+	middle = func(a, b int) int {
+		if b < a {
+			a, b = b, a
+		}
+		if id, ok := mids[[2]int{a, b}]; ok {
+			return id
+		}
+		pa, pb := mm.Coords[a].Point3d, mm.Coords[b].Point3d
+		id := int(mm.AddNode(
+			(pa[0]+pb[0])/2,
+			(pa[1]+pb[1])/2,
+			(pa[2]+pb[2])/2,
+		))
+		mids[[2]int{a, b}] = id
+		return id
+	}
+	for _, eid := range elements {
+		idx := mm.Elements[eid].Indexes
+		for k := range idx {
+			middle(idx[k], idx[(k+1)%len(idx)])
+		}
+	}
+	// split lines
+	for i, el := range mm.Elements {
+		if el.ElementType != Line2 {
+			continue
+		}
+		a, b := el.Indexes[0], el.Indexes[1]
+		if b < a {
+			a, b = b, a
+		}
+		id, ok := mids[[2]int{a, b}]
+		if !ok {
+			continue
+		}
+		mm.Elements[i].Indexes = []int{el.Indexes[0], id}
+		mm.AddLineByNodeNumber(uint(id), uint(el.Indexes[1]))
+	}
+	// split other plates on the same edges by transition triangles
+	selected := map[uint]bool{}
+	for _, eid := range elements {
+		selected[eid] = true
+	}
+	for i, el := range mm.Elements {
+		if selected[uint(i)] || !mm.isElement(el) ||
+			(el.ElementType != Triangle3 && el.ElementType != Quadr4) {
+			continue
+		}
+		var poly []int
+		first := -1
+		for k, a := range el.Indexes {
+			poly = append(poly, a)
+			b := el.Indexes[(k+1)%len(el.Indexes)]
+			if b < a {
+				a, b = b, a
+			}
+			id, ok := mids[[2]int{a, b}]
+			if !ok {
+				continue
+			}
+			if first < 0 {
+				first = len(poly)
+			}
+			poly = append(poly, id)
+		}
+		if first < 0 {
+			continue
+		}
+		// triangles around first middle node
+		poly = append(poly[first:], poly[:first]...)
+		// TODO loads on all elements
+		var ids []uint
+		for k := 2; k+1 < len(poly); k++ {
+			if id, ok := mm.AddTriangle3ByNodeNumber(
+				uint(poly[0]), uint(poly[k]), uint(poly[k+1])); ok {
+				ids = append(ids, id)
+			}
+		}
+		mm.Elements[i].ElementType = Triangle3
+		mm.Elements[i].Indexes = []int{poly[0], poly[1], poly[2]}
+		mm.copyGroups(uint(i), ids)
+	}
+	return
+}
+
+// SplitTri3To4Tri3 split triangles by middle nodes of edges into 4
+// triangles. Middle nodes are shared between triangles and lines on
+// edges are split too. Other plates on edges are split by transition
+// triangles.
+func (mm *Model) SplitTri3To4Tri3(elements []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("SplitTri3To4Tri3: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	var tris []uint
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		if mm.Elements[eid].ElementType == Triangle3 {
+			tris = append(tris, eid)
+		}
+	}
+	if len(tris) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	middle := mm.middleNodes(tris)
+	for _, eid := range tris {
+		idx := mm.Elements[eid].Indexes
+		a, b, c := idx[0], idx[1], idx[2]
+		ab, bc, ca := middle(a, b), middle(b, c), middle(c, a)
+		// TODO loads on all elements
+		mm.AddTriangle3ByNodeNumber(uint(a), uint(ab), uint(ca))
+		mm.AddTriangle3ByNodeNumber(uint(ab), uint(b), uint(bc))
+		mm.AddTriangle3ByNodeNumber(uint(ca), uint(bc), uint(c))
+		mm.Elements[eid].Indexes = []int{ab, bc, ca}
+	}
+}
+
+// SplitQuadr4To4Quadr4 split quadrilaterals by middle nodes of edges and
+// center node into 4 quadrilaterals or 2 triangles for not flat part.
+// Middle nodes are shared between quadrilaterals and lines on edges are
+// split too. Other plates on edges are split by transition triangles.
+func (mm *Model) SplitQuadr4To4Quadr4(elements []uint) {
+	// check
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("SplitQuadr4To4Quadr4: not valid elements id: %v", s)
+		return
+	}
+	// actions
+	var quadrs []uint
+	for _, eid := range uniqUint(append([]uint(nil), elements...)) {
+		if mm.Elements[eid].ElementType == Quadr4 {
+			quadrs = append(quadrs, eid)
+		}
+	}
+	if len(quadrs) == 0 {
+		// do nothing
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	middle := mm.middleNodes(quadrs)
+	for _, eid := range quadrs {
+		idx := mm.Elements[eid].Indexes
+		a, b, c, d := uint(idx[0]), uint(idx[1]), uint(idx[2]), uint(idx[3])
+		ab, bc := uint(middle(idx[0], idx[1])), uint(middle(idx[1], idx[2]))
+		cd, da := uint(middle(idx[2], idx[3])), uint(middle(idx[3], idx[0]))
+		o := centroid(mm.getPoint3d(eid))
+		center := mm.AddNode(o[0], o[1], o[2])
+		// TODO loads on all elements
+		first := [4]uint{a, ab, center, da}
+		if mm.isFlat(first) {
+			mm.Elements[eid].Indexes = []int{int(a), int(ab), int(center), int(da)}
+		} else {
+			trs := mm.quadrTriangles(first)
+			mm.Elements[eid].ElementType = Triangle3
+			mm.Elements[eid].Indexes = []int{int(trs[0][0]), int(trs[0][1]), int(trs[0][2])}
+			mm.AddTriangle3ByNodeNumber(trs[1][0], trs[1][1], trs[1][2])
+		}
+		mm.addPlate([4]uint{ab, b, bc, center})
+		mm.addPlate([4]uint{center, bc, c, cd})
+		mm.addPlate([4]uint{da, center, cd, d})
+	}
+}
+
 func (mm *Model) Hide(nodes, elements []uint) {
 	// check
 	if s := nodes; !mm.isValidNodeId(nodes) {
@@ -2325,113 +2669,178 @@ func (mm *Model) Copy(nodes, elements []uint,
 	}
 	nodes = uniqUint(nodes)
 	elements = uniqUint(elements)
-	// create copy of model
-	var cModel Model
-	for _, n := range nodes { // add all points
-		cModel.AddNode(
-			mm.Coords[n].Point3d[0],
-			mm.Coords[n].Point3d[1],
-			mm.Coords[n].Point3d[2],
-		)
+	// coordinates of copy
+	coords := make([][3]float64, len(nodes))
+	for i, n := range nodes {
+		coords[i] = mm.Coords[n].Point3d
 	}
-	for _, pe := range elements { // add all elements
-		el := mm.Elements[pe]
-		if el.ElementType == ElRemove {
-			// do nothing
-			continue
+	prev := map[uint]uint{}
+	for _, n := range nodes {
+		prev[n] = n
+	}
+	for _, path := range paths {
+		layer := map[uint]uint{}
+		for i, n := range nodes {
+			move(&coords[i], basePoint, path)
+			layer[n] = mm.AddNode(coords[i][0], coords[i][1], coords[i][2])
 		}
-		// find indexes of each point in new model
+		mm.copyLayer(nodes, elements, prev, layer, addLines, addTri)
+		prev = layer
+	}
+}
+
+// copyLayer create copy of elements by layer of nodes and connect copy
+// with previous layer by lines between nodes and by triangles between
+// lines. Layer is map of source nodes to nodes of copy.
+func (mm *Model) copyLayer(nodes, elements []uint,
+	prev, layer map[uint]uint,
+	addLines, addTri bool) {
+	for _, pe := range elements {
+		el := mm.Elements[pe]
 		ids := make([]uint, len(el.Indexes))
-		for i := range ids {
-			id := cModel.AddNode(
-				mm.Coords[el.Indexes[i]].Point3d[0],
-				mm.Coords[el.Indexes[i]].Point3d[1],
-				mm.Coords[el.Indexes[i]].Point3d[2],
-			)
-			ids[i] = id
+		for k := range ids {
+			ids[k] = layer[uint(el.Indexes[k])]
 		}
-		// create element in new model
+		// create element in copy
 		switch el.ElementType {
+		case ElRemove:
+			// do nothing
+			continue
 		case Line2:
-			cModel.AddLineByNodeNumber(ids[0], ids[1])
+			mm.AddLineByNodeNumber(ids[0], ids[1])
 		case Triangle3:
-			cModel.AddTriangle3ByNodeNumber(ids[0], ids[1], ids[2])
+			mm.AddTriangle3ByNodeNumber(ids[0], ids[1], ids[2])
 		case Quadr4:
-			cModel.AddQuadr4ByNodeNumber(ids[0], ids[1], ids[2], ids[3])
+			mm.AddQuadr4ByNodeNumber(ids[0], ids[1], ids[2], ids[3])
+		case Hexa8:
+			mm.AddHexa8ByNodeNumber([8]uint(ids))
 		default:
 			logger.Printf("Undefined: %v", el.ElementType)
 		}
+		// add triangles by lines
+		if addTri && el.ElementType == Line2 {
+			b0, b1 := prev[uint(el.Indexes[0])], prev[uint(el.Indexes[1])]
+			mm.AddTriangle3ByNodeNumber(b0, b1, ids[1])
+			mm.AddTriangle3ByNodeNumber(ids[1], ids[0], b0)
+		}
 	}
-	// main model
-	var (
-		beginCoord = make([]Coordinate, len(cModel.Coords))
-		endCoord   = make([]Coordinate, len(cModel.Coords))
-	)
-	copy(beginCoord, cModel.Coords)
-	for _, path := range paths {
-		for i := range cModel.Coords {
-			from := [3]float64(cModel.Coords[i].Point3d)
-			move(&from, basePoint, path)
-			cModel.Coords[i].Point3d = from
-		}
-		copy(endCoord, cModel.Coords)
-
-		if addLines {
-			var pre Model
-			for i := range beginCoord {
-				beginID := pre.AddNode(
-					beginCoord[i].Point3d[0],
-					beginCoord[i].Point3d[1],
-					beginCoord[i].Point3d[2],
-				)
-				endID := pre.AddNode(
-					endCoord[i].Point3d[0],
-					endCoord[i].Point3d[1],
-					endCoord[i].Point3d[2],
-				)
-				pre.AddLineByNodeNumber(beginID, endID)
+	if addLines {
+		for _, n := range nodes {
+			if prev[n] == layer[n] {
+				// zero lenght line
+				continue
 			}
-			mm.AddModel(pre)
+			mm.AddLineByNodeNumber(prev[n], layer[n])
 		}
-		if addTri {
-			var pre Model
-			for _, el := range cModel.Elements {
-				if el.ElementType != Line2 {
-					continue
-				}
-				var (
-					begin0 = pre.AddNode(
-						beginCoord[el.Indexes[0]].Point3d[0],
-						beginCoord[el.Indexes[0]].Point3d[1],
-						beginCoord[el.Indexes[0]].Point3d[2],
-					)
-					begin1 = pre.AddNode(
-						beginCoord[el.Indexes[1]].Point3d[0],
-						beginCoord[el.Indexes[1]].Point3d[1],
-						beginCoord[el.Indexes[1]].Point3d[2],
-					)
-					end0 = pre.AddNode(
-						endCoord[el.Indexes[0]].Point3d[0],
-						endCoord[el.Indexes[0]].Point3d[1],
-						endCoord[el.Indexes[0]].Point3d[2],
-					)
-					end1 = pre.AddNode(
-						endCoord[el.Indexes[1]].Point3d[0],
-						endCoord[el.Indexes[1]].Point3d[1],
-						endCoord[el.Indexes[1]].Point3d[2],
-					)
-				)
-				pre.AddTriangle3ByNodeNumber(begin0, begin1, end1)
-				pre.AddTriangle3ByNodeNumber(end1, end0, begin0)
+	}
+}
+
+// CopyByPath copy nodes and elements to each node of chain of path
+// lines. If withRotation is true, then copies are rotated by tangent of
+// path. If copyModel is false, then nodes and elements are moved to end
+// of path. Copies are connected by lines and triangles as in Copy.
+func (mm *Model) CopyByPath(nodes, elements []uint,
+	path []uint, // lines path
+	withRotation bool,
+	copyModel, addLines, addTri bool) {
+	// check
+	if s := nodes; !mm.isValidNodeId(nodes) {
+		logger.Printf("CopyByPath: not valid node id: %v", s)
+		return
+	}
+	if s := elements; !mm.isValidElementId(s, nil) {
+		logger.Printf("CopyByPath: not valid elements id: %v", s)
+		return
+	}
+	isLine := func(t ElType) bool { return t == Line2 }
+	if s := path; !mm.isValidElementId(s, isLine) {
+		logger.Printf("CopyByPath: not valid path id: %v", s)
+		return
+	}
+	// actions
+	if len(nodes) == 0 && len(elements) == 0 {
+		// do nothing
+		return
+	}
+	// nodes appending
+	for _, ie := range elements {
+		for _, ind := range mm.Elements[ie].Indexes {
+			nodes = append(nodes, uint(ind))
+		}
+	}
+	nodes = uniqUint(nodes)
+	elements = uniqUint(elements)
+	var ps []gog.Point3d
+	for _, n := range nodes {
+		ps = append(ps, mm.Coords[n].Point3d)
+	}
+	chain, ok := mm.pathChain(path, centroid(ps))
+	if !ok {
+		logger.Printf("CopyByPath: path is not chain of lines")
+		return
+	}
+	defer mm.DeselectAll() // deselect
+	// tangent of path in node
+	points := make([]gog.Point3d, len(chain))
+	for i, n := range chain {
+		points[i] = mm.Coords[n].Point3d
+	}
+	tangent := func(i int) (t [3]float64) {
+		for _, s := range [][2]int{{i - 1, i}, {i, i + 1}} {
+			if s[0] < 0 || len(points) <= s[1] {
+				continue
+			}
+			v := vector(points[s[0]], points[s[1]])
+			l := norm(v)
+			for k := range t {
+				t[k] += v[k] / l
 			}
-			mm.AddModel(pre)
 		}
-		beginCoord, endCoord = endCoord, beginCoord
-
-		copyBase := [3]float64{basePoint[0], basePoint[1], basePoint[2]}
-		move(&copyBase, basePoint, path)
-
-		mm.AddModel(cModel)
+		return
+	}
+	// transformation of point for node of path
+	transform := func(p gog.Point3d, i int) (r gog.Point3d) {
+		for k := range r {
+			r[k] = p[k] + points[i][k] - points[0][k]
+		}
+		if !withRotation {
+			return
+		}
+		t0, ti := tangent(0), tangent(i)
+		axis := cross(t0, ti)
+		if norm(axis) < gog.Eps3D {
+			if 0 < dot(t0, ti) {
+				return
+			}
+			// opposite direction
+			return rotate(r, points[i], anyNormal(t0), math.Pi)
+		}
+		angle := math.Atan2(norm(axis), dot(t0, ti))
+		l := norm(axis)
+		for k := range axis {
+			axis[k] /= l
+		}
+		return rotate(r, points[i], axis, angle)
+	}
+	if !copyModel {
+		last := len(points) - 1
+		for _, n := range nodes {
+			mm.Coords[n].Point3d = transform(mm.Coords[n].Point3d, last)
+		}
+		return
+	}
+	prev := map[uint]uint{}
+	for _, n := range nodes {
+		prev[n] = n
+	}
+	for i := 1; i < len(points); i++ {
+		layer := map[uint]uint{}
+		for _, n := range nodes {
+			p := transform(mm.Coords[n].Point3d, i)
+			layer[n] = mm.AddNode(p[0], p[1], p[2])
+		}
+		mm.copyLayer(nodes, elements, prev, layer, addLines, addTri)
+		prev = layer
 	}
 }
 
@@ -2489,59 +2898,33 @@ func (mm *Model) Mirror(nodes, elements []uint,
 		for i, n := range nodes {
 			mm.Coords[n].Point3d = mir[i]
 		}
+		for _, e := range elements {
+			if mm.Elements[e].ElementType == Hexa8 {
+				mm.outsideHexa8(mm.Elements[e].Indexes)
+			}
+		}
 		return
 	}
 	// copy mirror
-	var newID []uint
-	for i := range nodes { // create id of mirror nodes
-		id := mm.AddNode(mir[i][0], mir[i][1], mir[i][2])
-		newID = append(newID, id)
+	prev := map[uint]uint{}
+	layer := map[uint]uint{}
+	for i, n := range nodes { // create id of mirror nodes
+		prev[n] = n
+		layer[n] = mm.AddNode(mir[i][0], mir[i][1], mir[i][2])
 	}
-	// create mirror elements
-	for _, pe := range elements { // add all elements
-		el := mm.Elements[pe]
-		// find indexes of each point in new model
-		ids := make([]uint, len(el.Indexes))
-		for i := range ids { // copy old nodes ids
-			ids[i] = uint(el.Indexes[i])
-		}
-		for i := range ids { // convert to new ids
-			for pos, n := range nodes {
-				if n != ids[i] {
-					continue
-				}
-				ids[i] = newID[pos]
-				break
-			}
-		}
-		// create element in new model
-		switch el.ElementType {
-		case Line2:
-			mm.AddLineByNodeNumber(ids[0], ids[1])
-		case Triangle3:
-			mm.AddTriangle3ByNodeNumber(ids[0], ids[1], ids[2])
-		case Quadr4:
-			mm.AddQuadr4ByNodeNumber(ids[0], ids[1], ids[2], ids[3])
-		default:
-			logger.Printf("Undefined: %v", el.ElementType)
-		}
-		// add triangles by lines
-		if addTri {
-			if el.ElementType != Line2 {
-				continue
-			}
-			mm.AddTriangle3ByNodeNumber(uint(el.Indexes[0]), ids[0], uint(el.Indexes[1]))
-			mm.AddTriangle3ByNodeNumber(uint(el.Indexes[1]), ids[0], ids[1])
-		}
+	mm.copyLayer(nodes, elements, prev, layer, addLines, false)
+	// add triangles by lines
+	if !addTri {
+		return
 	}
-	if addLines {
-		for i := range nodes {
-			if newID[i] == nodes[i] {
-				// zero lenght line
-				continue
-			}
-			mm.AddLineByNodeNumber(nodes[i], newID[i])
+	for _, pe := range elements {
+		el := mm.Elements[pe]
+		if el.ElementType != Line2 {
+			continue
 		}
+		o0, o1 := uint(el.Indexes[0]), uint(el.Indexes[1])
+		mm.AddTriangle3ByNodeNumber(o0, layer[o0], o1)
+		mm.AddTriangle3ByNodeNumber(o1, layer[o0], layer[o1])
 	}
 }
 
@@ -2553,9 +2936,11 @@ func (mm *Model) GetRootGroup() groups.Group {
 	return &mm.Groups.meta
 }
 
+// Update is called after change of groups
 func (mm *Model) Update(nodes, elements *uint) {
-	logger.Printf("not implemented Update")
-	// TODO
+	// results is not valid for changed groups
+	mm.results = results{}
+	// TODO update nodes and elements
 }
 
 ///////////////////////////////////////////////////////////////////////////////
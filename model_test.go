@@ -35,6 +35,65 @@ func TestUniqUint(t *testing.T) {
 	}
 }
 
+func TestAddQuadr4ByNodeNumber(t *testing.T) {
+	tcs := []struct {
+		name   string
+		points [4][2]float64
+		expect [4]int
+	}{{
+		// lines of sides 2-3 and 4-1 intersect outside of trapezoid
+		name:   "trapezoid",
+		points: [4][2]float64{{0, 0}, {4, 0}, {3, 1}, {1, 1}},
+		expect: [4]int{0, 1, 2, 3},
+	}, {
+		name:   "self intersection",
+		points: [4][2]float64{{0, 0}, {1, 1}, {1, 0}, {0, 1}},
+		expect: [4]int{0, 1, 3, 2},
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var mm Model
+			var ns [4]uint
+			for i, p := range tc.points {
+				ns[i] = mm.AddNode(p[0], p[1], 0)
+			}
+			id, ok := mm.AddQuadr4ByNodeNumber(ns[0], ns[1], ns[2], ns[3])
+			if !ok {
+				t.Fatalf("quadrilateral is not added")
+			}
+			if act := [4]int(mm.Elements[id].Indexes); act != tc.expect {
+				t.Errorf("not valid order of nodes: %v != %v", act, tc.expect)
+			}
+		})
+	}
+}
+
+func TestRemoveFromGroups(t *testing.T) {
+	var mm Model
+	for i := 0; i < 4; i++ {
+		mm.AddNode(float64(i), 0, 0)
+	}
+	l0 := mm.AddLineByNodeNumber(0, 1)
+	l1 := mm.AddLineByNodeNumber(1, 2)
+	var rl groups.RigidLinks
+	rl.Master = 2
+	rl.Nodes = []uint{3}
+	var mat groups.Material
+	mat.Elements = []uint{l0, l1}
+	mm.Groups.meta.Groups = append(mm.Groups.meta.Groups, &rl, &mat)
+	mm.Remove([]uint{2}, nil)
+	if rl.Master != groups.NoMaster || len(rl.Nodes) != 1 {
+		t.Errorf("master node is not removed: %v", rl)
+	}
+	if len(mat.Elements) != 1 || mat.Elements[0] != l0 {
+		t.Errorf("removed line in group: %v", mat.Elements)
+	}
+	mm.Remove(nil, []uint{l0})
+	if len(mat.Elements) != 0 {
+		t.Errorf("removed line in group: %v", mat.Elements)
+	}
+}
+
 // goos: linux
 // goarch: amd64
 // pkg: github.com/Konstantin8105/ms
@@ -3,10 +3,12 @@ package ms
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/Konstantin8105/ds"
 	"github.com/Konstantin8105/glsymbol"
 	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
 	"github.com/Konstantin8105/pow"
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -29,6 +31,7 @@ type Opengl struct {
 	state       viewState
 	cursorLeft  viewState
 	updateModel bool
+	zoom        bool // zoom to selected nodes and elements
 	camera      struct {
 		alpha, betta float64
 		R            float64
@@ -38,6 +41,33 @@ type Opengl struct {
 	cube struct {
 		min, max gog.Point3d
 	}
+	deformed struct {
+		show    bool
+		scale   float64   // zero is automatic scale
+		mode    uint      // zero is static deformation, other is mode shape
+		buckl   bool      // shape of buckling mode
+		animate bool      // animation of mode shape
+		start   time.Time // start of animation
+	}
+	dimensions struct {
+		list []Dimension
+		// matrixes of 3d view for text of dimensions on screen
+		modelview, projection [16]float64
+	}
+	contour struct {
+		field    ResultField
+		deformed bool
+		scale    float64 // zero is automatic scale
+		quality  bool    // color by metric of element quality
+		metric   Metric
+
+		// prepared values for drawing
+		coords           []gog.Point3d // coordinates of nodes in view
+		nodes, elements  []float64     // values of field
+		min, max         float64
+		minAt, maxAt     gog.Point3d
+		minName, maxName string
+	}
 
 	// mouses
 	mouses   [3]Mouse  // left, middle, right
@@ -169,6 +199,18 @@ func (op *Opengl) Draw(x, y, w, h int32) {
 	openGlScreenCoordinate(x, y, w, h)
 	op.drawAxes(w, h)
 
+	// legend of contour view
+	if op.state == colorResults {
+		openGlScreenCoordinate(x, y, w, h)
+		op.drawLegend(w, h)
+	}
+
+	// text of dimensions
+	if len(op.dimensions.list) != 0 {
+		openGlScreenCoordinate(x, y, w, h)
+		op.drawDimensionsText(w, h)
+	}
+
 	// minimal screen notes
 	openGlScreenCoordinate(x, y, w, h)
 	if op.mesh != nil {
@@ -212,6 +254,9 @@ func (op *Opengl) Draw(x, y, w, h int32) {
 		if op.cursorLeft&selectQuadrs != 0 {
 			name += fmt.Sprintf(" %s", selectQuadrs)
 		}
+		if op.cursorLeft&selectHexas != 0 {
+			name += fmt.Sprintf(" %s", selectHexas)
+		}
 		gl.Color3ub(0, 0, 0) // black
 		op.font.Printf(10, float32(h)-50, name)
 	}
@@ -227,7 +272,7 @@ func (op *Opengl) Draw(x, y, w, h int32) {
 
 func (op *Opengl) Init() {
 	op.updateModel = true
-	if op.state != normal && op.state != colorEdgeElements {
+	if op.state != normal && op.state != colorEdgeElements && op.state != colorResults {
 		op.state = normal
 	}
 	op.cursorLeft = selectPoints
@@ -298,6 +343,12 @@ func (op *Opengl) ViewAll() {
 	op.updateModel = true
 }
 
+// ZoomSelected update camera for view selected nodes and elements only
+func (op *Opengl) ZoomSelected() {
+	op.updateModel = true
+	op.zoom = true
+}
+
 func (op *Opengl) UpdateModel() {
 	op.updateModel = true
 	// TODO  add logic
@@ -387,12 +438,29 @@ func (op *Opengl) model3d(s viewState, fill selectState) {
 			return
 		}
 		// for empty coordinate no need to do anythink
-		var ps []gog.Point3d
+		var ps, zs []gog.Point3d
 		for i := range cos {
 			if cos[i].hided || cos[i].Removed {
 				continue
 			}
 			ps = append(ps, cos[i].Point3d)
+			if cos[i].selected {
+				zs = append(zs, cos[i].Point3d)
+			}
+		}
+		if op.zoom {
+			op.zoom = false
+			for _, el := range op.mesh.GetElements() {
+				if !el.selected || el.ElementType == ElRemove {
+					continue
+				}
+				for _, p := range el.Indexes {
+					zs = append(zs, cos[p].Point3d)
+				}
+			}
+			if len(zs) != 0 {
+				ps = zs
+			}
 		}
 		// update camera
 		min, max := gog.BorderPoints3d(ps...)
@@ -411,6 +479,10 @@ func (op *Opengl) model3d(s viewState, fill selectState) {
 			(max[2] + min[2]) / 2.0,
 		}
 		op.camera.R *= 0.5 + 0.05
+		if op.camera.R < gog.Eps3D {
+			// single point
+			op.camera.R = 1.0
+		}
 		op.cube.min = min
 		op.cube.max = max
 	}
@@ -435,14 +507,529 @@ func (op *Opengl) model3d(s viewState, fill selectState) {
 
 	if !(s == selectTriangles ||
 		s == selectQuadrs ||
+		s == selectHexas ||
 		s == selectLines ||
 		s == selectPoints) {
 		// TODO CREATE A GREAT LINES
 		gl.Disable(gl.POLYGON_OFFSET_FILL)
 	}
 
+	if s == colorResults {
+		op.prepareContour()
+	}
 	op.drawElements(s, fill)
 	op.drawPoints(s, fill)
+	op.drawGroups(s)
+	op.drawDeformed(s)
+	op.drawContourMarkers(s)
+	op.drawDimensions(s)
+}
+
+// drawDimensions draw lines of dimensions in 3d view
+func (op *Opengl) drawDimensions(s viewState) {
+	if len(op.dimensions.list) == 0 {
+		return
+	}
+	switch s {
+	case selectPoints, selectLines, selectTriangles, selectQuadrs, selectHexas:
+		return
+	}
+	gl.GetDoublev(gl.MODELVIEW_MATRIX, &op.dimensions.modelview[0])
+	gl.GetDoublev(gl.PROJECTION_MATRIX, &op.dimensions.projection[0])
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		gl.Enable(gl.DEPTH_TEST)
+	}()
+	gl.Color3ub(255, 0, 255) // magenta
+	gl.LineWidth(2)
+	gl.Begin(gl.LINES)
+	for _, d := range op.dimensions.list {
+		gl.Vertex3d(d.From[0], d.From[1], d.From[2])
+		gl.Vertex3d(d.To[0], d.To[1], d.To[2])
+	}
+	gl.End()
+	gl.PointSize(6)
+	gl.Begin(gl.POINTS)
+	for _, d := range op.dimensions.list {
+		gl.Vertex3d(d.From[0], d.From[1], d.From[2])
+		gl.Vertex3d(d.To[0], d.To[1], d.To[2])
+	}
+	gl.End()
+}
+
+// drawDimensionsText draw text of dimensions in middle of dimension
+// lines in screen coordinates
+func (op *Opengl) drawDimensionsText(w, h int32) {
+	m, p := op.dimensions.modelview, op.dimensions.projection
+	// multiply column-major matrix and vector
+	mul := func(a [16]float64, v [4]float64) (r [4]float64) {
+		for i := range r {
+			for k := range v {
+				r[i] += a[k*4+i] * v[k]
+			}
+		}
+		return
+	}
+	gl.Color3ub(255, 0, 255) // magenta
+	for _, d := range op.dimensions.list {
+		var v [4]float64
+		for i := 0; i < 3; i++ {
+			v[i] = (d.From[i] + d.To[i]) / 2
+		}
+		v[3] = 1
+		c := mul(p, mul(m, v))
+		if c[3] == 0 {
+			continue
+		}
+		x := (c[0]/c[3] + 1) / 2 * float64(w)
+		y := (c[1]/c[3] + 1) / 2 * float64(h)
+		op.font.Printf(float32(x)+5, float32(y)+5, d.Text)
+	}
+}
+
+// AddDimension add temporary annotation of measurement in 3d view
+func (op *Opengl) AddDimension(d Dimension) {
+	op.dimensions.list = append(op.dimensions.list, d)
+}
+
+// ClearDimensions remove all annotations of measurements
+func (op *Opengl) ClearDimensions() {
+	op.dimensions.list = nil
+}
+
+// screenAxes return unit vectors of screen X and Y directions in
+// model coordinates. Used for symbols with constant size on screen.
+func (op *Opengl) screenAxes() (ux, uy gog.Point3d) {
+	a := op.camera.alpha * radToDegree
+	b := op.camera.betta * radToDegree
+	ux = gog.Point3d{math.Cos(a), 0, math.Sin(a)}
+	uy = gog.Point3d{math.Sin(a) * math.Sin(b), math.Cos(b), -math.Cos(a) * math.Sin(b)}
+	return
+}
+
+// symbolSize is size of group symbols in model coordinates
+func (op *Opengl) symbolSize() float64 {
+	return op.camera.R * 0.015
+}
+
+func drawCircle(center gog.Point3d, radius float64, ux, uy gog.Point3d) {
+	const segments = 16
+	gl.Begin(gl.LINE_LOOP)
+	for i := 0; i < segments; i++ {
+		angle := 2.0 * math.Pi * float64(i) / float64(segments)
+		c, s := radius*math.Cos(angle), radius*math.Sin(angle)
+		gl.Vertex3d(
+			center[0]+c*ux[0]+s*uy[0],
+			center[1]+c*ux[1]+s*uy[1],
+			center[2]+c*ux[2]+s*uy[2],
+		)
+	}
+	gl.End()
+}
+
+// drawSupport draw symbol of node support in direction `d`:
+//
+//   - fixed translation is line with ground bar
+//   - spring translation is zigzag line
+//   - prescribed translation is arrow
+//   - fixed rotation is square around axis
+//   - spring rotation is circle around axis
+//   - prescribed rotation is circle with arrow along axis
+func drawSupport(p gog.Point3d, d int, kind groups.SupportKind, size float64) {
+	if kind == groups.Free {
+		return
+	}
+	// local axes: `e` along direction, `u` and `v` perpendicular
+	var e, u, v gog.Point3d
+	e[d%3] = 1
+	u[(d+1)%3] = 1
+	v[(d+2)%3] = 1
+	at := func(a, b, c float64) {
+		gl.Vertex3d(
+			p[0]+a*e[0]+b*u[0]+c*v[0],
+			p[1]+a*e[1]+b*u[1]+c*v[1],
+			p[2]+a*e[2]+b*u[2]+c*v[2],
+		)
+	}
+	L := 3 * size
+	if d < 3 {
+		switch kind {
+		case groups.Fixed:
+			gl.Begin(gl.LINES)
+			at(0, 0, 0)
+			at(-L, 0, 0)
+			at(-L, -size, 0)
+			at(-L, size, 0)
+			gl.End()
+		case groups.Spring:
+			const zigzag = 6
+			gl.Begin(gl.LINE_STRIP)
+			at(0, 0, 0)
+			for i := 1; i < zigzag; i++ {
+				b := size / 2
+				if i%2 == 0 {
+					b = -b
+				}
+				at(-L*float64(i)/zigzag, b, 0)
+			}
+			at(-L, 0, 0)
+			gl.End()
+		case groups.Displacement:
+			gl.Begin(gl.LINES)
+			at(-L, 0, 0)
+			at(0, 0, 0)
+			at(0, 0, 0)
+			at(-size, size/2, 0)
+			at(0, 0, 0)
+			at(-size, -size/2, 0)
+			gl.End()
+		}
+		return
+	}
+	switch kind {
+	case groups.Fixed:
+		gl.Begin(gl.LINE_LOOP)
+		at(0, -size, -size)
+		at(0, size, -size)
+		at(0, size, size)
+		at(0, -size, size)
+		gl.End()
+	case groups.Spring:
+		drawCircle(p, size, u, v)
+	case groups.Displacement:
+		drawCircle(p, size, u, v)
+		gl.Begin(gl.LINES)
+		at(0, 0, 0)
+		at(L, 0, 0)
+		gl.End()
+	}
+}
+
+func (op *Opengl) drawGroups(s viewState) {
+	if s != normal && s != colorEdgeElements && s != colorResults {
+		return
+	}
+	cos := op.mesh.GetCoords()
+	els := op.mesh.GetElements()
+
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		gl.Enable(gl.DEPTH_TEST)
+	}()
+	gl.LineWidth(1)
+	gl.Disable(gl.LINE_SMOOTH)
+
+	size := op.symbolSize()
+	ux, uy := op.screenAxes()
+
+	walkGroups(op.mesh.GetRootGroup(), func(gr groups.Group) {
+		switch g := gr.(type) {
+		case *groups.NodeSupports:
+			gl.Color3ub(0, 150, 0) // green
+			for _, id := range g.Nodes {
+				if len(cos) <= int(id) || cos[id].Removed || cos[id].hided {
+					continue
+				}
+				for d := range g.Direction {
+					drawSupport(cos[id].Point3d, d, g.Direction[d].Kind, size)
+				}
+			}
+		case *groups.RigidLinks:
+			if uint(len(cos)) <= g.Master || cos[g.Master].Removed {
+				break
+			}
+			gl.Color3ub(200, 100, 0) // orange
+			gl.LineStipple(1, 0x00FF)
+			gl.Enable(gl.LINE_STIPPLE)
+			gl.Begin(gl.LINES)
+			for _, id := range g.Nodes {
+				if len(cos) <= int(id) || cos[id].Removed {
+					continue
+				}
+				if cos[g.Master].hided && cos[id].hided {
+					continue
+				}
+				gl.Vertex3d(
+					cos[g.Master].Point3d[0],
+					cos[g.Master].Point3d[1],
+					cos[g.Master].Point3d[2],
+				)
+				gl.Vertex3d(
+					cos[id].Point3d[0],
+					cos[id].Point3d[1],
+					cos[id].Point3d[2],
+				)
+			}
+			gl.End()
+			gl.Disable(gl.LINE_STIPPLE)
+		case *groups.LineReleases:
+			gl.Color3ub(0, 0, 200) // blue
+			for _, id := range g.Elements {
+				if len(els) <= int(id) {
+					continue
+				}
+				el := els[id]
+				if el.ElementType != Line2 || el.hided {
+					continue
+				}
+				for side, dir := range [2][6]bool{g.Begin, g.End} {
+					released := false
+					for i := range dir {
+						released = released || dir[i]
+					}
+					if !released {
+						continue
+					}
+					from := cos[el.Indexes[side]].Point3d
+					to := cos[el.Indexes[1-side]].Point3d
+					L := gog.Distance3d(from, to)
+					if L < gog.Eps3D {
+						continue
+					}
+					// circle near end of line
+					offset := math.Min(2.0*size, 0.25*L) / L
+					center := gog.PointLineRatio3d(from, to, offset)
+					drawCircle(center, size, ux, uy)
+				}
+			}
+		}
+	})
+}
+
+// deformedScale return scale of displacements for deformed shape
+func (op *Opengl) deformedScale(ds [][6]float64, scale float64) float64 {
+	if scale != 0 {
+		return scale
+	}
+	var dmax float64
+	for _, d := range ds {
+		dmax = math.Max(dmax, math.Sqrt(d[0]*d[0]+d[1]*d[1]+d[2]*d[2]))
+	}
+	if dmax == 0 {
+		return 0
+	}
+	// maximal displacement is part of model size
+	return 0.2 * op.camera.R / dmax
+}
+
+func (op *Opengl) drawDeformed(s viewState) {
+	if s != normal && s != colorEdgeElements {
+		return
+	}
+	if !op.deformed.show {
+		return
+	}
+	cos := op.mesh.GetCoords()
+	els := op.mesh.GetElements()
+	ds := op.mesh.GetDisplacements()
+	if 0 < op.deformed.mode {
+		if op.deformed.buckl {
+			ds = op.mesh.GetBucklingShape(op.deformed.mode - 1)
+		} else {
+			ds = op.mesh.GetModeShape(op.deformed.mode - 1)
+		}
+	}
+	if len(ds) != len(cos) {
+		return
+	}
+	scale := op.deformedScale(ds, op.deformed.scale)
+	if scale == 0 {
+		return
+	}
+	if op.deformed.animate {
+		// period of animation is 2 seconds
+		scale *= math.Sin(math.Pi * time.Since(op.deformed.start).Seconds())
+	}
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		gl.Enable(gl.DEPTH_TEST)
+	}()
+	gl.LineWidth(2)
+	gl.Color3ub(200, 0, 0) // red
+	for _, el := range els {
+		if el.hided {
+			continue
+		}
+		switch el.ElementType {
+		case Line2:
+			gl.Begin(gl.LINES)
+		case Triangle3, Quadr4:
+			gl.Begin(gl.LINE_LOOP)
+		default:
+			continue
+		}
+		for _, p := range el.Indexes {
+			gl.Vertex3d(
+				cos[p].Point3d[0]+scale*ds[p][0],
+				cos[p].Point3d[1]+scale*ds[p][1],
+				cos[p].Point3d[2]+scale*ds[p][2],
+			)
+		}
+		gl.End()
+	}
+	gl.LineWidth(1)
+}
+
+// prepareContour calculate values of scalar field and coordinates of
+// nodes for contour view
+func (op *Opengl) prepareContour() {
+	c := &op.contour
+	cos := op.mesh.GetCoords()
+	els := op.mesh.GetElements()
+	c.coords = make([]gog.Point3d, len(cos))
+	for i := range cos {
+		c.coords[i] = cos[i].Point3d
+	}
+	if c.deformed {
+		ds := op.mesh.GetDisplacements()
+		if len(ds) == len(cos) {
+			scale := op.deformedScale(ds, c.scale)
+			for i := range c.coords {
+				for d := 0; d < 3; d++ {
+					c.coords[i][d] += scale * ds[i][d]
+				}
+			}
+		}
+	}
+	if c.quality {
+		c.nodes, c.elements = nil, op.mesh.Quality(c.metric)
+	} else {
+		c.nodes, c.elements = op.mesh.GetField(c.field)
+	}
+	if len(c.nodes) != len(cos) {
+		c.nodes = nil
+	}
+	if len(c.elements) != len(els) {
+		c.elements = nil
+	}
+	c.min, c.max = math.Inf(1), math.Inf(-1)
+	update := func(v float64, at gog.Point3d, name string) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+		if v < c.min {
+			c.min, c.minAt, c.minName = v, at, name
+		}
+		if c.max < v {
+			c.max, c.maxAt, c.maxName = v, at, name
+		}
+	}
+	for i, v := range c.nodes {
+		if cos[i].Removed || cos[i].hided {
+			continue
+		}
+		update(v, c.coords[i], fmt.Sprintf("node %d", i))
+	}
+	for i, v := range c.elements {
+		el := els[i]
+		if el.hided || el.ElementType == ElRemove {
+			continue
+		}
+		var mid gog.Point3d
+		for _, k := range el.Indexes {
+			for d := 0; d < 3; d++ {
+				mid[d] += c.coords[k][d] / float64(len(el.Indexes))
+			}
+		}
+		update(v, mid, fmt.Sprintf("element %d", i))
+	}
+}
+
+// contourColor return color of element in node for contour view
+func (op *Opengl) contourColor(iel, node int) (r, g, b uint8) {
+	c := &op.contour
+	v := math.NaN()
+	if c.nodes != nil {
+		v = c.nodes[node]
+	} else if c.elements != nil {
+		v = c.elements[iel]
+	}
+	if math.IsNaN(v) || c.max < c.min {
+		return 155, 155, 155 // gray for value without result
+	}
+	t := 0.5
+	if c.min < c.max {
+		t = (v - c.min) / (c.max - c.min)
+	}
+	return scaleColor(t)
+}
+
+// scaleColor return color of ratio from 0 to 1 by scale
+// blue - cyan - green - yellow - red
+func scaleColor(t float64) (r, g, b uint8) {
+	t = math.Max(0, math.Min(1, t))
+	f := func(v float64) uint8 { return uint8(255 * v) }
+	switch {
+	case t < 0.25:
+		return 0, f(t / 0.25), 255
+	case t < 0.5:
+		return 0, 255, f((0.5 - t) / 0.25)
+	case t < 0.75:
+		return f((t - 0.5) / 0.25), 255, 0
+	}
+	return 255, f((1 - t) / 0.25), 0
+}
+
+// drawContourMarkers draw points of minimal and maximal values of field
+func (op *Opengl) drawContourMarkers(s viewState) {
+	c := &op.contour
+	if s != colorResults || c.max < c.min {
+		return
+	}
+	gl.Disable(gl.DEPTH_TEST)
+	defer func() {
+		gl.Enable(gl.DEPTH_TEST)
+	}()
+	gl.PointSize(12)
+	gl.Begin(gl.POINTS)
+	gl.Color3ub(0, 0, 255) // blue
+	gl.Vertex3d(c.minAt[0], c.minAt[1], c.minAt[2])
+	gl.Color3ub(255, 0, 0) // red
+	gl.Vertex3d(c.maxAt[0], c.maxAt[1], c.maxAt[2])
+	gl.End()
+}
+
+// drawLegend draw scale of colors with values of field in screen
+// coordinates
+func (op *Opengl) drawLegend(w, h int32) {
+	c := &op.contour
+	const (
+		steps  = 10
+		width  = 20
+		height = 20
+	)
+	x := float64(w) - 150
+	y := float64(h) - 80
+	gl.Color3ub(0, 0, 0) // black
+	if c.quality {
+		op.font.Printf(float32(x), float32(y)+10, c.metric.String())
+	} else {
+		op.font.Printf(float32(x), float32(y)+10, c.field.String())
+	}
+	if c.max < c.min {
+		op.font.Printf(float32(x), float32(y)-15, "no results")
+		return
+	}
+	for i := 0; i < steps; i++ {
+		// from maximal value at top to minimal value at bottom
+		t := 1 - (float64(i)+0.5)/steps
+		r, g, b := scaleColor(t)
+		gl.Color3ub(r, g, b)
+		top := y - float64(i)*height
+		gl.Begin(gl.QUADS)
+		gl.Vertex2d(x, top)
+		gl.Vertex2d(x+width, top)
+		gl.Vertex2d(x+width, top-height)
+		gl.Vertex2d(x, top-height)
+		gl.End()
+		v := c.min + (1-float64(i)/steps)*(c.max-c.min)
+		gl.Color3ub(0, 0, 0) // black
+		op.font.Printf(float32(x+width+5), float32(top-5), fmt.Sprintf("%10.3e", v))
+	}
+	bottom := float32(y - steps*height)
+	op.font.Printf(float32(x+width+5), bottom-5, fmt.Sprintf("%10.3e", c.min))
+	op.font.Printf(float32(x)-20, bottom-25, fmt.Sprintf("max at %s", c.maxName))
+	op.font.Printf(float32(x)-20, bottom-40, fmt.Sprintf("min at %s", c.minName))
 }
 
 func (op *Opengl) drawPoints(s viewState, fill selectState) {
@@ -457,6 +1044,23 @@ func (op *Opengl) drawPoints(s viewState, fill selectState) {
 	const pointSize = 4
 	// Point
 	switch s {
+	case colorResults:
+		gl.PointSize(pointSize)
+		gl.Begin(gl.POINTS)
+		for i := range cos {
+			if cos[i].Removed || cos[i].hided {
+				continue
+			}
+			if cos[i].selected {
+				r, g, b = 255, 1, 1
+			} else {
+				r, g, b = 1, 1, 1
+			}
+			gl.Color3ub(r, g, b)
+			p := op.contour.coords[i]
+			gl.Vertex3d(p[0], p[1], p[2])
+		}
+		gl.End()
 	case normal, colorEdgeElements:
 		gl.PointSize(pointSize)
 		gl.Begin(gl.POINTS)
@@ -494,7 +1098,7 @@ func (op *Opengl) drawPoints(s viewState, fill selectState) {
 			gl.Vertex3d(cos[i].Point3d[0], cos[i].Point3d[1], cos[i].Point3d[2])
 		}
 		gl.End()
-	case selectLines, selectTriangles, selectQuadrs:
+	case selectLines, selectTriangles, selectQuadrs, selectHexas:
 		// do nothing
 	default:
 		logger.Printf("not valid selection : %v", s)
@@ -506,11 +1110,11 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 	els := op.mesh.GetElements()
 
 	switch s {
-	case normal, colorEdgeElements:
+	case normal, colorEdgeElements, colorResults:
 		gl.ShadeModel(gl.SMOOTH) // for points color
 		gl.Enable(gl.POLYGON_OFFSET_FILL)
 		gl.PolygonOffset(1.0, 1.0)
-	case selectPoints, selectLines, selectTriangles, selectQuadrs:
+	case selectPoints, selectLines, selectTriangles, selectQuadrs, selectHexas:
 		gl.ShadeModel(gl.FLAT)
 		gl.Disable(gl.LINE_SMOOTH)
 		gl.Disable(gl.POLYGON_OFFSET_FILL)
@@ -548,7 +1152,7 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 		// 			continue
 		// 		}
 		// do not show selected elements in Select case
-		if s != normal && s != colorEdgeElements && el.selected {
+		if s != normal && s != colorEdgeElements && s != colorResults && el.selected {
 			continue
 		}
 		if el.hided { // hided element
@@ -591,6 +1195,17 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 					gl.Vertex3d(c.Point3d[0], c.Point3d[1], c.Point3d[2])
 				}
 				gl.End()
+			case colorResults:
+				gl.LineWidth(5)
+				gl.Enable(gl.LINE_SMOOTH)
+				gl.Begin(gl.LINES)
+				for _, k := range el.Indexes {
+					r, g, b = op.contourColor(iel, k)
+					gl.Color3ub(r, g, b)
+					c := op.contour.coords[k]
+					gl.Vertex3d(c[0], c[1], c[2])
+				}
+				gl.End()
 			case selectPoints:
 				// do nothing
 			case selectLines:
@@ -609,7 +1224,7 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 				}
 			case selectTriangles:
 				// do nothing
-			case selectQuadrs:
+			case selectQuadrs, selectHexas:
 				// do nothing
 			default:
 				logger.Printf("undefined type: %v", s)
@@ -688,9 +1303,18 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 					gl.Vertex3d(c.Point3d[0], c.Point3d[1], c.Point3d[2])
 				}
 				gl.End()
+			case colorResults:
+				gl.Begin(gl.POLYGON)
+				for _, k := range el.Indexes {
+					r, g, b = op.contourColor(iel, k)
+					gl.Color3ub(r, g, b)
+					c := op.contour.coords[k]
+					gl.Vertex3d(c[0], c[1], c[2])
+				}
+				gl.End()
 			case selectPoints:
 				// do nothing
-			case selectLines:
+			case selectLines, selectHexas:
 				// do nothing
 			case selectTriangles, selectQuadrs:
 				if (s == selectTriangles && el.ElementType != Triangle3) ||
@@ -713,6 +1337,67 @@ func (op *Opengl) drawElements(s viewState, fill selectState) {
 				logger.Printf("undefined type: %v", s)
 			}
 		///////////////////////////////////
+		case Hexa8:
+			faces := func(color func(face, p, k int) (r, g, b uint8)) {
+				for f, face := range hexaFaces {
+					gl.Begin(gl.POLYGON)
+					for p, k := range face {
+						gl.Color3ub(color(f, p, el.Indexes[k]))
+						c := cos[el.Indexes[k]]
+						gl.Vertex3d(c.Point3d[0], c.Point3d[1], c.Point3d[2])
+					}
+					gl.End()
+				}
+			}
+			switch s {
+			case normal:
+				if el.selected {
+					r, g, b = 235, 70, 70
+				} else {
+					r, g, b = 0, 90, 160
+				}
+				gl.Color3ub(r, g, b)
+				gl.LineWidth(1)
+				gl.Disable(gl.LINE_SMOOTH)
+				gl.Begin(gl.LINES)
+				for k := 0; k < 4; k++ {
+					for _, e := range [3][2]int{
+						{k, (k + 1) % 4},     // bottom
+						{4 + k, 4 + (k+1)%4}, // top
+						{k, k + 4},           // vertical
+					} {
+						for _, p := range e {
+							c := cos[el.Indexes[p]]
+							gl.Vertex3d(c.Point3d[0], c.Point3d[1], c.Point3d[2])
+						}
+					}
+				}
+				gl.End()
+			case colorEdgeElements:
+				faces(func(face, p, k int) (r, g, b uint8) {
+					if el.selected {
+						return 255, 90, 90
+					}
+					return edgeColor(face % 4)
+				})
+			case colorResults:
+				faces(func(face, p, k int) (r, g, b uint8) {
+					return op.contourColor(iel, k)
+				})
+			case selectPoints, selectLines, selectTriangles, selectQuadrs:
+				// do nothing
+			case selectHexas:
+				if fill {
+					faces(func(face, p, k int) (r, g, b uint8) {
+						return convertToColor(iel)
+					})
+				} else {
+					randomPoint(iel)
+				}
+			default:
+				logger.Printf("undefined type: %v", s)
+			}
+		///////////////////////////////////
 		default:
 			logger.Printf("undefined type: %v", s)
 			// switch s {
@@ -836,6 +1521,8 @@ const (
 	selectLines                             // 8
 	selectTriangles                         // 16
 	selectQuadrs                            // 32
+	colorResults                            // 64
+	selectHexas                             // 128
 )
 
 type selectState bool
@@ -859,6 +1546,10 @@ func (s viewState) String() string {
 		return "triangles"
 	case selectQuadrs:
 		return "quadrs"
+	case selectHexas:
+		return "hexas"
+	case colorResults:
+		return "Results state"
 	}
 	return fmt.Sprintf("%d", s)
 }
@@ -1042,6 +1733,58 @@ func (op *Opengl) StandardView(view SView) {
 	}
 }
 
+func (op *Opengl) DeformedShape(show bool, scale float64) {
+	op.deformed.show = show
+	op.deformed.scale = scale
+	op.deformed.mode = 0
+	op.deformed.animate = false
+}
+
+func (op *Opengl) BucklingShape(show bool, mode uint, scale float64) {
+	op.deformed.show = show
+	op.deformed.scale = scale
+	op.deformed.mode = mode + 1
+	op.deformed.buckl = true
+	op.deformed.animate = false
+}
+
+func (op *Opengl) ModeShape(show bool, mode uint, scale float64, animate bool) {
+	op.deformed.show = show
+	op.deformed.scale = scale
+	op.deformed.mode = mode + 1
+	op.deformed.buckl = false
+	op.deformed.animate = animate
+	op.deformed.start = time.Now()
+}
+
+func (op *Opengl) Contour(show bool, field ResultField, deformed bool, scale float64) {
+	if !show {
+		if op.state == colorResults {
+			op.state = normal
+		}
+		return
+	}
+	op.state = colorResults
+	op.contour.quality = false
+	op.contour.field = field
+	op.contour.deformed = deformed
+	op.contour.scale = scale
+}
+
+// QualityView color elements by metric of quality
+func (op *Opengl) QualityView(show bool, metric Metric) {
+	if !show {
+		if op.state == colorResults {
+			op.state = normal
+		}
+		return
+	}
+	op.state = colorResults
+	op.contour.quality = true
+	op.contour.metric = metric
+	op.contour.deformed = false
+}
+
 func (op *Opengl) ColorEdge(isColor bool) {
 	if isColor {
 		op.state = colorEdgeElements
@@ -1056,7 +1799,7 @@ func (op *Opengl) SelectLeftCursor(nodes bool, elements []bool) {
 		op.cursorLeft |= selectPoints
 	}
 	for el := Line2; el < lastElement; el = el + 1 {
-		if !elements[int(el)] {
+		if len(elements) <= int(el) || !elements[int(el)] {
 			continue
 		}
 		op.cursorLeft |= el.getSelect()
@@ -1083,6 +1826,7 @@ const (
 	AddLinesLC LeftCursor = iota
 	AddTrianglesLC
 	AddQuardsLC
+	AddHexasLC
 	endLC
 )
 
@@ -1362,6 +2106,20 @@ func (ms *MouseSelect) Action(op *Opengl) {
 			}
 			els[index].selected = true
 			return true
+		}}, {st: selectHexas, sf: func(index int) bool {
+			if index < 0 {
+				return false
+			}
+			if len(els) <= index {
+				logger.Printf("selectHexas index outside: %d", index)
+				return false
+			}
+			if els[index].ElementType != Hexa8 {
+				logger.Printf("selectHexas index is not hexa: %d", index)
+				return false
+			}
+			els[index].selected = true
+			return true
 		}},
 	} {
 		if op.cursorLeft&s.st == 0 {
@@ -1622,6 +2380,8 @@ func (ma *MouseAdd) Action(op *Opengl) {
 			ma.ps[2],
 			ma.ps[3],
 		)
+	case AddHexasLC:
+		op.mesh.AddHexa8ByNodeNumber([8]uint(ma.ps))
 	}
 	ma.Reset()
 }
@@ -0,0 +1,190 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Konstantin8105/gog"
+)
+
+// Metric is measure of element quality
+type Metric uint8
+
+const (
+	AspectRatio Metric = iota // ratio of longest edge to inscribed circle
+	MinAngle                  // minimal corner angle in degrees
+	MaxAngle                  // maximal corner angle in degrees
+	Skew                      // equiangle skew from 0 to 1
+	Warping                   // warping angle of quadrilateral in degrees
+	Length                    // length of line
+	endMetric
+)
+
+func (m Metric) String() string {
+	switch m {
+	case AspectRatio:
+		return "Aspect ratio"
+	case MinAngle:
+		return "Minimal angle"
+	case MaxAngle:
+		return "Maximal angle"
+	case Skew:
+		return "Skew"
+	case Warping:
+		return "Warping"
+	case Length:
+		return "Length"
+	}
+	return fmt.Sprintf("Undefined metric %d", uint8(m))
+}
+
+// plateAngles return corner angles of plate in degrees
+func plateAngles(ps []gog.Point3d) (angles []float64) {
+	n := len(ps)
+	for i := range ps {
+		a := vector(ps[i], ps[(i+n-1)%n])
+		b := vector(ps[i], ps[(i+1)%n])
+		l := norm(a) * norm(b)
+		if l == 0 {
+			angles = append(angles, 0)
+			continue
+		}
+		cos := math.Max(-1, math.Min(1, dot(a, b)/l))
+		angles = append(angles, math.Acos(cos)*180/math.Pi)
+	}
+	return
+}
+
+// quality return value of metric for points of element. Result is NaN
+// for metric not defined for type of element.
+func quality(metric Metric, ps []gog.Point3d) float64 {
+	if len(ps) == 2 {
+		if metric == Length {
+			return gog.Distance3d(ps[0], ps[1])
+		}
+		return math.NaN()
+	}
+	if len(ps) != 3 && len(ps) != 4 || metric == Length {
+		return math.NaN()
+	}
+	angles := plateAngles(ps)
+	amin, amax := min(angles...), max(angles...)
+	switch metric {
+	case AspectRatio:
+		var edges []float64
+		for i := range ps {
+			edges = append(edges, gog.Distance3d(ps[i], ps[(i+1)%len(ps)]))
+		}
+		if len(ps) == 4 {
+			if min(edges...) == 0 {
+				return math.Inf(1)
+			}
+			return max(edges...) / min(edges...)
+		}
+		// ratio of longest edge to diameter of inscribed circle,
+		// equilateral triangle is 1
+		area := plateArea(ps)
+		if area == 0 {
+			return math.Inf(1)
+		}
+		perimeter := edges[0] + edges[1] + edges[2]
+		return max(edges...) * perimeter / (4 * math.Sqrt(3) * area)
+	case MinAngle:
+		return amin
+	case MaxAngle:
+		return amax
+	case Skew:
+		// angle of ideal element
+		ideal := 60.0
+		if len(ps) == 4 {
+			ideal = 90.0
+		}
+		return math.Max((amax-ideal)/(180-ideal), (ideal-amin)/ideal)
+	case Warping:
+		if len(ps) == 3 {
+			return 0
+		}
+		return quadrWarping([4]gog.Point3d(ps))
+	}
+	return math.NaN()
+}
+
+// Quality return value of metric for each element. Not defined value
+// is NaN.
+func (mm *Model) Quality(metric Metric) (values []float64) {
+	values = make([]float64, len(mm.Elements))
+	for i, el := range mm.Elements {
+		values[i] = math.NaN()
+		if el.ElementType == ElRemove {
+			continue
+		}
+		var ps []gog.Point3d
+		for _, p := range el.Indexes {
+			ps = append(ps, mm.Coords[p].Point3d)
+		}
+		values[i] = quality(metric, ps)
+	}
+	return
+}
+
+// QualityHistogram return amount of elements in each of equal
+// intervals between minimal and maximal value of metric
+func (mm *Model) QualityHistogram(metric Metric, intervals uint) (
+	from, to float64,
+	amount []uint,
+) {
+	logger.Printf("QualityHistogram")
+	if intervals == 0 {
+		return
+	}
+	values := mm.Quality(metric)
+	from, to = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		from, to = math.Min(from, v), math.Max(to, v)
+	}
+	if to < from {
+		// without values
+		return 0, 0, nil
+	}
+	amount = make([]uint, intervals)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		i := intervals - 1
+		if !math.IsInf(v, 1) && from < to {
+			if k := uint((v - from) / (to - from) * float64(intervals)); k < i {
+				i = k
+			}
+		}
+		amount[i]++
+	}
+	return
+}
+
+// SelectByQuality select elements with value of metric between
+// values from and to
+func (mm *Model) SelectByQuality(metric Metric, from, to float64) {
+	// check
+	for _, p := range []*float64{&from, &to} {
+		if !mm.isValidValue(*p) {
+			logger.Printf("SelectByQuality: not valid value: %v", *p)
+			return
+		}
+	}
+	// actions
+	if to < from {
+		from, to = to, from
+	}
+	values := mm.Quality(metric)
+	mm.filterByElement(func(e ElType) bool { // filter
+		return e != ElRemove
+	}, func(id int) { // run
+		if v := values[id]; from <= v && v <= to {
+			mm.Elements[id].selected = true
+		}
+	})
+}
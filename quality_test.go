@@ -0,0 +1,46 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestQuality(t *testing.T) {
+	var mm Model
+	for _, c := range [][3]float64{
+		{0, 0, 0}, {1, 0, 0}, {0.5, math.Sqrt(3) / 2, 0}, // equilateral
+		{2, 0, 0}, {4, 0, 0}, {4, 1, 0}, {2, 1, 0}, // rectangle 2x1
+	} {
+		mm.AddNode(c[0], c[1], c[2])
+	}
+	mm.AddTriangle3ByNodeNumber(0, 1, 2)
+	mm.AddQuadr4ByNodeNumber(3, 4, 5, 6)
+	mm.AddLineByNodeNumber(1, 3)
+	for _, tc := range []struct {
+		metric Metric
+		exp    [3]float64
+	}{
+		{AspectRatio, [3]float64{1, 2, math.NaN()}},
+		{MinAngle, [3]float64{60, 90, math.NaN()}},
+		{MaxAngle, [3]float64{60, 90, math.NaN()}},
+		{Skew, [3]float64{0, 0, math.NaN()}},
+		{Warping, [3]float64{0, 0, math.NaN()}},
+		{Length, [3]float64{math.NaN(), math.NaN(), 1}},
+	} {
+		vs := mm.Quality(tc.metric)
+		for i := range tc.exp {
+			if math.IsNaN(tc.exp[i]) != math.IsNaN(vs[i]) || 1e-9 < math.Abs(vs[i]-tc.exp[i]) {
+				t.Errorf("%s of element %d: %v != %v", tc.metric, i, vs[i], tc.exp[i])
+			}
+		}
+	}
+	from, to, amount := mm.QualityHistogram(AspectRatio, 2)
+	if 1e-9 < math.Abs(from-1) || to != 2 || fmt.Sprint(amount) != "[1 1]" {
+		t.Errorf("not valid histogram: %v %v %v", from, to, amount)
+	}
+	mm.SelectByQuality(AspectRatio, 1.5, 3)
+	if els := mm.GetSelectElements(false, nil); fmt.Sprint(els) != "[1]" {
+		t.Errorf("not valid selection: %v", els)
+	}
+}
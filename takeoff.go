@@ -0,0 +1,151 @@
+package ms
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
+)
+
+// Takeoff is mass properties and quantities of elements
+type Takeoff struct {
+	Name string
+	// Length of lines
+	Length float64
+	// Area of plates
+	Area float64
+	// Paint area of plates for both sides. Lines is not included,
+	// because perimeter of section is unknown.
+	Paint float64
+	// Weight is mass of lines by section area and of plates by
+	// thickness for density of material
+	Weight float64
+	// Centroid is center of mass. For zero weight is center of nodes.
+	Centroid gog.Point3d
+	// Min, Max is bounding box of nodes
+	Min, Max gog.Point3d
+}
+
+// takeoff return quantities of nodes and elements
+func (mm *Model) takeoff(
+	name string,
+	nodes, elements []uint,
+	density, area, thickness map[uint]float64,
+) (t Takeoff) {
+	t.Name = name
+	var ps []gog.Point3d
+	for _, n := range nodes {
+		if int(n) < len(mm.Coords) && !mm.Coords[n].Removed {
+			ps = append(ps, mm.Coords[n].Point3d)
+		}
+	}
+	var moment [3]float64
+	for _, e := range uniqUint(append([]uint(nil), elements...)) {
+		if len(mm.Elements) <= int(e) || mm.Elements[e].ElementType == ElRemove {
+			continue
+		}
+		eps := mm.getPoint3d(e)
+		ps = append(ps, eps...)
+		var weight float64
+		switch mm.Elements[e].ElementType {
+		case Line2:
+			length := gog.Distance3d(eps[0], eps[1])
+			t.Length += length
+			weight = length * area[e] * density[e]
+		case Triangle3, Quadr4:
+			a := plateArea(eps)
+			t.Area += a
+			t.Paint += 2 * a
+			weight = a * thickness[e] * density[e]
+		}
+		t.Weight += weight
+		c := centroid(eps)
+		for i := range moment {
+			moment[i] += weight * c[i]
+		}
+	}
+	if len(ps) == 0 {
+		return
+	}
+	t.Min, t.Max = gog.BorderPoints3d(ps...)
+	if 0 < t.Weight {
+		for i := range moment {
+			t.Centroid[i] = moment[i] / t.Weight
+		}
+	} else {
+		t.Centroid = centroid(ps)
+	}
+	return
+}
+
+// Takeoff return mass properties and quantities for each named list and
+// for all elements of model in last row
+func (mm *Model) Takeoff() (rows []Takeoff) {
+	logger.Printf("Takeoff")
+	density := map[uint]float64{}
+	area := map[uint]float64{}
+	thickness := map[uint]float64{}
+	var lists []*groups.NamedList
+	walkGroups(mm.GetRootGroup(), func(gr groups.Group) {
+		switch g := gr.(type) {
+		case *groups.Material:
+			for _, id := range g.Elements {
+				density[id] = g.Density
+			}
+		case *groups.Section:
+			for _, id := range g.Elements {
+				area[id] = g.A
+			}
+		case *groups.Thickness:
+			for _, id := range g.Elements {
+				thickness[id] = g.Thickness
+			}
+		case *groups.NamedList:
+			lists = append(lists, g)
+		}
+	})
+	for _, l := range lists {
+		rows = append(rows, mm.takeoff(l.Named.String(), l.Nodes, l.Elements,
+			density, area, thickness))
+	}
+	all := make([]uint, len(mm.Elements))
+	for i := range all {
+		all[i] = uint(i)
+	}
+	rows = append(rows, mm.takeoff("TOTAL", nil, all, density, area, thickness))
+	return
+}
+
+// TakeoffTable return table of takeoff in CSV format
+func (mm *Model) TakeoffTable() (table string) {
+	table = "Name, Length, Area, Paint area, Weight, " +
+		"Centroid X, Centroid Y, Centroid Z, " +
+		"Min X, Min Y, Min Z, Max X, Max Y, Max Z\n"
+	f := func(v float64) string {
+		if math.Abs(v) < 1e-12 {
+			v = 0
+		}
+		return fmt.Sprintf("%.6g", v)
+	}
+	for _, t := range mm.Takeoff() {
+		name := strings.ReplaceAll(t.Name, ",", ";")
+		table += fmt.Sprintf("%s, %s, %s, %s, %s", name,
+			f(t.Length), f(t.Area), f(t.Paint), f(t.Weight))
+		for _, p := range []gog.Point3d{t.Centroid, t.Min, t.Max} {
+			for _, v := range p {
+				table += ", " + f(v)
+			}
+		}
+		table += "\n"
+	}
+	return
+}
+
+// ExportTakeoff write takeoff in CSV file
+func (mm *Model) ExportTakeoff(filename string) (err error) {
+	logger.Printf("ExportTakeoff")
+	return os.WriteFile(filename, []byte(mm.TakeoffTable()), 0666)
+}
@@ -0,0 +1,52 @@
+package ms
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Konstantin8105/ms/groups"
+)
+
+func TestTakeoff(t *testing.T) {
+	var mm Model
+	for _, c := range [][3]float64{
+		{0, 0, 0}, {2, 0, 0}, {2, 1, 0}, {0, 1, 0},
+	} {
+		mm.AddNode(c[0], c[1], c[2])
+	}
+	mm.AddLineByNodeNumber(0, 1)
+	mm.AddQuadr4ByNodeNumber(0, 1, 2, 3)
+	meta := &mm.Groups.meta
+	meta.Groups = append(meta.Groups,
+		&groups.Material{Density: 7850, Elements: []uint{0, 1}},
+		&groups.Section{A: 0.01, Elements: []uint{0}},
+		&groups.Thickness{Thickness: 0.005, Elements: []uint{1}},
+		&groups.NamedList{Named: groups.Named{Name: "beam"}, Elements: []uint{0}},
+	)
+	rows := mm.Takeoff()
+	if len(rows) != 2 {
+		t.Fatalf("not valid amount of rows: %d", len(rows))
+	}
+	isNear := func(name string, act, exp float64) {
+		t.Helper()
+		if 1e-9 < math.Abs(act-exp) {
+			t.Errorf("%s: %v != %v", name, act, exp)
+		}
+	}
+	beam, total := rows[0], rows[1]
+	isNear("beam length", beam.Length, 2)
+	isNear("beam weight", beam.Weight, 2*0.01*7850)
+	isNear("beam centroid", beam.Centroid[0], 1)
+	isNear("total area", total.Area, 2)
+	isNear("total paint", total.Paint, 4)
+	isNear("total weight", total.Weight, 2*0.01*7850+2*0.005*7850)
+	// plate centroid is (1, 0.5) with half of beam weight
+	isNear("total centroid", total.Centroid[1], 0.5/3)
+	isNear("bounding box", total.Max[1], 1)
+	table := mm.TakeoffTable()
+	if lines := strings.Split(strings.TrimSpace(table), "\n"); len(lines) != 3 ||
+		!strings.HasPrefix(lines[1], "BEAM, 2, 0, 0, 157") {
+		t.Errorf("not valid table:\n%s", table)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strconv"
+	"strings"
 
 	"github.com/Konstantin8105/ds"
 	"github.com/Konstantin8105/gog"
@@ -27,17 +28,17 @@ const (
 	Hide
 	MoveCopy
 	// 	TypModels
-	// 	Check
+	Check
+	Measurement
+	Analysis
 	Plugin
 	endGroup
 )
 
 // TODO metadata (add,change,select): thickness, local axe, section
 // TODO Array by line, circular
-// TODO distance between 2 points if selected both
 // TODO betta angle for repeat rotate Copy
 // TODO check copy node on distance
-// TODO split elements by plane
 
 func (g GroupID) String() string {
 	switch g {
@@ -59,10 +60,14 @@ func (g GroupID) String() string {
 		return "Hide"
 	case MoveCopy:
 		return "Move/Copy/Mirror"
-		// 	case Check:
-		// 		return "Check"
+	case Check:
+		return "Check"
+	case Measurement:
+		return "Measurement"
 		// 	case TypModels:
 		// 		return "Typical models"
+	case Analysis:
+		return "Analysis"
 	case Plugin:
 		return "Plugin"
 	}
@@ -513,6 +518,7 @@ type Viewable interface {
 	StandardView(view SView)
 	ColorEdge(isColor bool)
 	ViewAll()
+	ZoomSelected()
 	// View node number
 	// View line number
 	// View element number
@@ -597,6 +603,7 @@ type AddRemovable interface {
 	AddLineByNodeNumber(n1, n2 uint) (id uint)
 	AddTriangle3ByNodeNumber(n1, n2, n3 uint) (id uint, ok bool)
 	AddQuadr4ByNodeNumber(n1, n2, n3, n4 uint) (id uint, ok bool)
+	AddHexa8ByNodeNumber(ns [8]uint) (id uint, ok bool)
 
 	AddModel(m Model)
 
@@ -620,12 +627,12 @@ type AddRemovable interface {
 	SplitLinesByRatio(lines []uint, proportional float64, atBegin bool)
 	SplitLinesByEqualParts(lines []uint, parts uint)
 	SplitTri3To3Tri3(tris []uint)
-	// SplitTri3To4Tri3(tris []uint)
+	SplitTri3To4Tri3(tris []uint)
 	// TODO REMOVE SplitTri3To3Quadr4(tris string)
 	// SplitTri3To2Tri3(tris string, side uint)
 	// SplitQuadr4To2Quadr4(q4s string, side uint)
 	// Quadr4 to 4 Triangle3
-	// Quadr4 to 4 Quadr4
+	SplitQuadr4To4Quadr4(q4s []uint)
 	// Triangles3, Quadrs4 by Lines2
 	// LeftCursor split triangle by edge
 
@@ -641,9 +648,13 @@ type AddRemovable interface {
 	// MergeTriangles()
 	// MergeMesh()
 
-	// Triangulation by nodes
+	// Triangulation by nodes and constrained lines
+	Triangulation(nodes, lines []uint) (triangles []uint)
+	// Mesh of plates inside closed loops of lines
+	MeshArea(lines []uint, size float64, quadr bool) (elements []uint)
 	// Triangulation exist plates by area
 	// Smooth mesh
+	SmoothMesh(plates []uint, iterations uint, guarded bool) (before, after float64)
 
 	// Scale by ratio [sX,sY,sZ] and node
 	ScaleOrtho(
@@ -664,7 +675,8 @@ type AddRemovable interface {
 	// Fillet plates
 	// Explode plates
 
-	// create section by plane
+	// split elements by plane and create section by plane
+	SplitByPlane(plane [3]gog.Point3d, elements []uint, section bool) (lines []uint)
 
 	// remove
 	Remove(nodes, elements []uint)
@@ -982,6 +994,175 @@ func init() {
 				initn()
 			}
 		}}, {
+		Name: "Split Triangle3 to 4 Triangle3",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			ns, nsgt, initn := Select("Select triangles3", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Triangle3
+				})
+			})
+			list.Add(ns)
+
+			var bi vl.Button
+			bi.SetText("Split")
+			bi.OnClick = func() {
+				m.SplitTri3To4Tri3(nsgt())
+			}
+			list.Add(&bi)
+
+			return &list, func() {
+				initn()
+			}
+		}}, {
+		Name: "Split Quadr4 to 4 Quadr4",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			ns, nsgt, initn := Select("Select quadr4", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Quadr4
+				})
+			})
+			list.Add(ns)
+
+			var bi vl.Button
+			bi.SetText("Split")
+			bi.OnClick = func() {
+				m.SplitQuadr4To4Quadr4(nsgt())
+			}
+			list.Add(&bi)
+
+			return &list, func() {
+				initn()
+			}
+		}}, {
+		Name: "Split elements by plane",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var inits []func()
+			es, esgt, inite := Select("Select elements", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, nil)
+			})
+			list.Add(es)
+			inits = append(inits, inite)
+
+			type path struct {
+				w    vl.Widget
+				getC func() (plane [3]gog.Point3d, ok bool)
+			}
+			var paths []path
+			{
+				var ch vl.CollapsingHeader
+				ch.BorderIfClosed(false)
+				ch.SetText("Plane by 3 points:")
+
+				var list vl.List
+
+				var gts []func() []uint
+				for i := 1; i <= 3; i++ {
+					n, ngt, initn := Select(fmt.Sprintf("Select node %d:", i), Single, m.GetSelectNodes)
+					list.Add(n)
+					inits = append(inits, initn)
+					gts = append(gts, ngt)
+				}
+				ch.SetRoot(&list)
+
+				paths = append(paths, path{
+					w: &ch,
+					getC: func() (plane [3]gog.Point3d, ok bool) {
+						for i := range plane {
+							ns := gts[i]()
+							if len(ns) != 1 {
+								return
+							}
+							if plane[i], ok = m.GetCoordByID(ns[0]); !ok {
+								return
+							}
+						}
+						return
+					},
+				})
+			}
+			{
+				var ch vl.CollapsingHeader
+				ch.BorderIfClosed(false)
+				ch.SetText("Node and normal of plane:")
+
+				var list vl.List
+
+				n, ngt, initn := Select("Select node", Single, m.GetSelectNodes)
+				list.Add(n)
+				inits = append(inits, initn)
+
+				var gts []func() (float64, bool)
+				for _, name := range []string{"X", "Y", "Z"} {
+					w, gt, initw := InputFloat("Normal "+name+":", "", 0)
+					list.Add(w)
+					inits = append(inits, initw)
+					gts = append(gts, gt)
+				}
+				ch.SetRoot(&list)
+
+				paths = append(paths, path{
+					w: &ch,
+					getC: func() (plane [3]gog.Point3d, ok bool) {
+						ns := ngt()
+						if len(ns) != 1 {
+							return
+						}
+						coord, ok := m.GetCoordByID(ns[0])
+						if !ok {
+							return
+						}
+						var normal [3]float64
+						for i := range normal {
+							if normal[i], ok = gts[i](); !ok {
+								return
+							}
+						}
+						// axes on plane
+						u := anyNormal(normal)
+						v := cross(normal, u)
+						plane[0] = coord
+						for i := range coord {
+							plane[1][i] = coord[i] + u[i]
+							plane[2][i] = coord[i] + v[i]
+						}
+						return plane, true
+					},
+				})
+			}
+
+			list.Add(new(vl.Separator))
+			list.Add(vl.TextStatic("Choose plane:"))
+			var param vl.RadioGroup
+			for i := range paths {
+				param.Add(paths[i].w)
+			}
+			list.Add(&param)
+
+			var section vl.CheckBox
+			section.SetText("Add section lines")
+			list.Add(&section)
+
+			var b vl.Button
+			b.SetText("Split")
+			b.OnClick = func() {
+				pos := param.GetPos()
+				plane, ok := paths[pos].getC()
+				if !ok {
+					return
+				}
+				m.SplitByPlane(plane, esgt(), section.Checked)
+			}
+			list.Add(&b)
+			return &list, func() {
+				for i := range inits {
+					inits[i]()
+				}
+			}
+		}}, {
 		Name: "Intersection between nodes and elements",
 		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
 			var list vl.List
@@ -1049,6 +1230,101 @@ func init() {
 				inits()
 			}
 		}}, {
+		Name: "Triangulation by nodes",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			ns, nsgt, initn := Select("Select nodes", Many, m.GetSelectNodes)
+			list.Add(ns)
+			ls, lsgt, initl := Select("Select constrained lines", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			})
+			list.Add(ls)
+
+			var b vl.Button
+			b.SetText("Triangulation")
+			b.OnClick = func() {
+				m.Triangulation(nsgt(), lsgt())
+			}
+			list.Add(&b)
+			return &list, func() {
+				initn()
+				initl()
+			}
+		}}, {
+		Name: "Mesh area by boundary lines",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			ls, lsgt, initl := Select("Select boundary lines", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			})
+			list.Add(ls)
+
+			size, sgt, inits := InputFloat("Size of elements", "meter", 1.0)
+			list.Add(size)
+
+			var rg vl.RadioGroup
+			rg.AddText("Triangles", "Mostly quadrilaterals")
+			list.Add(&rg)
+
+			var b vl.Button
+			b.SetText("Mesh")
+			b.OnClick = func() {
+				s, ok := sgt()
+				if !ok {
+					return
+				}
+				m.MeshArea(lsgt(), s, rg.GetPos() == 1)
+			}
+			list.Add(&b)
+			return &list, func() {
+				initl()
+				inits()
+			}
+		}}, {
+		Name: "Smooth mesh",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			ps, psgt, initp := Select("Select plates", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Triangle3 || t == Quadr4
+				})
+			})
+			list.Add(ps)
+
+			iter, igt, initi := InputUnsigned("Iterations", "", 10)
+			list.Add(iter)
+
+			var rg vl.RadioGroup
+			rg.AddText("Laplacian", "Laplacian without decreasing of quality")
+			list.Add(&rg)
+
+			var res vl.Text
+			var b vl.Button
+			b.SetText("Smooth")
+			b.OnClick = func() {
+				n, ok := igt()
+				if !ok {
+					return
+				}
+				before, after := m.SmoothMesh(psgt(), n, rg.GetPos() == 1)
+				res.SetText(fmt.Sprintf("Minimal angle of plates:\nbefore: %.2f deg\nafter: %.2f deg",
+					before, after))
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				initp()
+				initi()
+				res.SetText("")
+			}
+		}}, {
 		Name: "Scale ortho by direction X,Y,Z",
 		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
 			var list vl.List
@@ -1720,12 +1996,12 @@ type MoveCopyble interface {
 	//		plane Plane,
 	//		intermediantParts uint,
 	//		copy, addLines, addTri bool)
-	//	CopyByPath(nodes, elements []uint,
-	//		path []uint, // lines path
-	//		withRotation bool,
-	//		copy, addLines, addTri bool) // Copy by line path
+	CopyByPath(nodes, elements []uint,
+		path []uint, // lines path
+		withRotation bool,
+		copy, addLines, addTri bool) // Copy by line path
 	// Bend
-	// Loft
+	Loft(from, to []uint, sections uint) (created []uint) // Loft between profiles
 	// Translational repeat
 	// Circular repeat/Spiral
 }
@@ -2365,6 +2641,97 @@ func init() {
 					}
 				}
 			}
+		}}, {
+		Name: "Copy by path",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var inits []func()
+			ns, coordgt, elgt, initsel := SelectAll(m)
+			list.Add(ns)
+			inits = append(inits, initsel)
+
+			ps, psgt, initp := Select("Select path lines", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			})
+			list.Add(ps)
+			inits = append(inits, initp)
+
+			var rotation vl.CheckBox
+			rotation.SetText("Rotate by tangent of path")
+			inits = append(inits, func() { rotation.Checked = false })
+			list.Add(&rotation)
+
+			// copy or move
+			var mir vl.RadioGroup
+			mir.Add(vl.TextStatic("Move - no copy"))
+
+			var cop vl.List
+			var lines vl.CheckBox
+			lines.SetText("Add intermediant lines")
+			inits = append(inits, func() { lines.Checked = false })
+			cop.Add(&lines)
+			var tris vl.CheckBox
+			tris.SetText("Add intermediant triangles")
+			inits = append(inits, func() { tris.Checked = false })
+			cop.Add(&tris)
+			mir.Add(&cop)
+
+			list.Add(&mir)
+
+			// operation
+			list.Add(new(vl.Separator))
+			var b vl.Button
+			b.SetText("Copy")
+			b.OnClick = func() {
+				m.CopyByPath(coordgt(), elgt(),
+					psgt(),
+					rotation.Checked,
+					mir.GetPos() == 1,
+					lines.Checked, tris.Checked)
+			}
+			list.Add(&b)
+			return &list, func() {
+				for i := range inits {
+					if f := inits[i]; f != nil {
+						f()
+					}
+				}
+			}
+		}}, {
+		Name: "Loft",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			isLine := func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			}
+			from, fromgt, initf := Select("Select first profile lines", Many, isLine)
+			list.Add(from)
+			to, togt, initt := Select("Select second profile lines", Many, isLine)
+			list.Add(to)
+
+			sec, secgt, inits := InputUnsigned("Amount intermediate sections", "", 4)
+			list.Add(sec)
+
+			var b vl.Button
+			b.SetText("Loft")
+			b.OnClick = func() {
+				n, ok := secgt()
+				if !ok {
+					return
+				}
+				m.Loft(fromgt(), togt(), n)
+			}
+			list.Add(&b)
+			return &list, func() {
+				initf()
+				initt()
+				inits()
+			}
 		}},
 	}
 	for i := range ops {
@@ -2375,15 +2742,21 @@ func init() {
 
 type Checkable interface {
 	Check() error
-	// CheckSingleStructure()     // Multiple structures
+	CheckAll() (findings []Finding)
+	CheckSingleStructure() (parts []Structure)     // Multiple structures
+	CheckDuplicateElements() (duplicates [][]uint) // Beam and plate duplicate
+	RemoveDuplicateElements()
+	CheckLinesOverlapping() (pairs [][2]uint) // Overlapping collinear beams
+	FixLinesOverlapping()
+	CheckPlates(warping float64) (defects [endPlateDefect][]uint) // Plates not valid FE
+	Quality(metric Metric) (values []float64)
+	QualityHistogram(metric Metric, intervals uint) (from, to float64, amount []uint)
+	SelectByQuality(metric Metric, from, to float64)
+	QualityView(show bool, metric Metric)
 	// CheckDuplicateNodes()      // Node duplicate
-	// CheckDuplicateLines()      // Beam duplicate
-	// CheckDuplicateTriangles()  // Plate duplicate
 	// CheckZeroLenghtLine()      // Zero length beam
 	// CheckZeroLenghtTriangles() // Zero length plates
 	// CheckElementsIndexes()     // Check FE on Indexes lenght
-	// CheckTriangleOnOneLine()   // Plates not valid FE
-	// CheckLinesOverlapping()    // Overlapping Collinear beams
 	// CheckFreeNodes()           // Not connected nodes
 	// CheckValidCoordinates()    // no NaN, infinite
 	// Empty loads
@@ -2393,27 +2766,824 @@ type Checkable interface {
 	// All ortho elements
 }
 
-// func init() {
-// 	ops := []Operation{{}}
-// 	for i := range ops {
-// 		ops[i].Group = Check
-// 	}
-// 	Operations = append(Operations, ops...)
-// }
+func init() {
+	group := Check
+	ops := []Operation{{
+		Name: "Check model",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var findings []Finding
+			var res vl.Text
+			var cb vl.ComboBox
+
+			show := func() {
+				pos := int(cb.GetPos())
+				if len(findings) <= pos {
+					return
+				}
+				m.Select(findings[pos].Nodes, findings[pos].Elements)
+				m.ZoomSelected()
+			}
+
+			var b vl.Button
+			b.SetText("Check")
+			b.OnClick = func() {
+				findings = m.CheckAll()
+				cb.Clear()
+				var amount [endSeverity]int
+				for _, f := range findings {
+					cb.Add(f.String())
+					amount[f.Severity]++
+				}
+				cb.OnChange = show
+				if len(findings) == 0 {
+					res.SetText("Model is valid")
+					return
+				}
+				var out string
+				for s := range amount {
+					out += fmt.Sprintf("%s: %d\n", Severity(s), amount[s])
+				}
+				res.SetText(out)
+			}
+			list.Add(&b)
+			list.Add(&res)
+			list.Add(&cb)
+
+			var s vl.Button
+			s.SetText("Select and zoom")
+			s.OnClick = show
+			list.Add(&s)
+			return &list, func() {
+				findings = nil
+				cb.Clear()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Single structure",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var parts []Structure
+			var res vl.Text
+			var cb vl.ComboBox
+
+			var b vl.Button
+			b.SetText("Check")
+			b.OnClick = func() {
+				parts = m.CheckSingleStructure()
+				cb.Clear()
+				for i, p := range parts {
+					cb.Add(fmt.Sprintf("Part %d: %d nodes, %d elements",
+						i+1, len(p.Nodes), len(p.Elements)))
+				}
+				switch len(parts) {
+				case 0:
+					res.SetText("Model is empty")
+				case 1:
+					res.SetText("Model is single structure")
+				default:
+					res.SetText(fmt.Sprintf("Amount of disconnected parts: %d", len(parts)))
+				}
+			}
+			list.Add(&b)
+			list.Add(&res)
+			list.Add(&cb)
+
+			var s vl.Button
+			s.SetText("Select part")
+			s.OnClick = func() {
+				pos := int(cb.GetPos())
+				if len(parts) <= pos {
+					return
+				}
+				m.Select(parts[pos].Nodes, parts[pos].Elements)
+			}
+			list.Add(&s)
+			return &list, func() {
+				parts = nil
+				cb.Clear()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Duplicate elements",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var duplicates [][]uint
+			var res vl.Text
+			var cb vl.ComboBox
+
+			check := func() {
+				duplicates = m.CheckDuplicateElements()
+				cb.Clear()
+				for _, d := range duplicates {
+					cb.Add(fmt.Sprintf("Elements: %v", d))
+				}
+				res.SetText(fmt.Sprintf("Amount of duplicates: %d", len(duplicates)))
+			}
+
+			var b vl.Button
+			b.SetText("Check")
+			b.OnClick = check
+			list.Add(&b)
+			list.Add(&res)
+			list.Add(&cb)
+
+			var s vl.Button
+			s.SetText("Select duplicates")
+			s.OnClick = func() {
+				pos := int(cb.GetPos())
+				if len(duplicates) <= pos {
+					return
+				}
+				m.Select(nil, duplicates[pos])
+			}
+			list.Add(&s)
+
+			var r vl.Button
+			r.SetText("Remove duplicates")
+			r.OnClick = func() {
+				m.RemoveDuplicateElements()
+				check()
+			}
+			list.Add(&r)
+			return &list, func() {
+				duplicates = nil
+				cb.Clear()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Overlapping lines",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var pairs [][2]uint
+			var res vl.Text
+			var cb vl.ComboBox
+
+			check := func() {
+				pairs = m.CheckLinesOverlapping()
+				cb.Clear()
+				for _, p := range pairs {
+					cb.Add(fmt.Sprintf("Lines: %d, %d", p[0], p[1]))
+				}
+				res.SetText(fmt.Sprintf("Amount of overlapping: %d", len(pairs)))
+			}
+
+			var b vl.Button
+			b.SetText("Check")
+			b.OnClick = check
+			list.Add(&b)
+			list.Add(&res)
+			list.Add(&cb)
+
+			var s vl.Button
+			s.SetText("Select lines")
+			s.OnClick = func() {
+				pos := int(cb.GetPos())
+				if len(pairs) <= pos {
+					return
+				}
+				m.Select(nil, pairs[pos][:])
+			}
+			list.Add(&s)
+
+			var r vl.Button
+			r.SetText("Split and merge")
+			r.OnClick = func() {
+				m.FixLinesOverlapping()
+				check()
+			}
+			list.Add(&r)
+			return &list, func() {
+				pairs = nil
+				cb.Clear()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Plates geometry",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var defects [endPlateDefect][]uint
+			wa, wagt, initwa := InputFloat("Warping tolerance", "degree", 5)
+			list.Add(wa)
+
+			var cb vl.ComboBox
+			var b vl.Button
+			b.SetText("Check")
+			b.OnClick = func() {
+				warping, ok := wagt()
+				if !ok {
+					return
+				}
+				defects = m.CheckPlates(warping)
+				cb.Clear()
+				for d := range defects {
+					cb.Add(fmt.Sprintf("%s: %d", PlateDefect(d), len(defects[d])))
+				}
+			}
+			list.Add(&b)
+			list.Add(&cb)
+
+			var s vl.Button
+			s.SetText("Select plates")
+			s.OnClick = func() {
+				pos := int(cb.GetPos())
+				if len(defects) <= pos {
+					return
+				}
+				m.Select(nil, defects[pos])
+			}
+			list.Add(&s)
+			return &list, func() {
+				defects = [endPlateDefect][]uint{}
+				cb.Clear()
+				initwa()
+			}
+		}}, {
+		Name: "Mesh quality",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var metrics vl.ComboBox
+			for q := AspectRatio; q < endMetric; q++ {
+				metrics.Add(q.String())
+			}
+			list.Add(&metrics)
+
+			in, ingt, initin := InputUnsigned("Intervals", "", 10)
+			list.Add(in)
+
+			var res vl.Text
+			var b vl.Button
+			b.SetText("Histogram")
+			b.OnClick = func() {
+				intervals, ok := ingt()
+				if !ok {
+					return
+				}
+				from, to, amount := m.QualityHistogram(Metric(metrics.GetPos()), intervals)
+				if len(amount) == 0 {
+					res.SetText("No elements")
+					return
+				}
+				var most uint
+				for _, a := range amount {
+					if most < a {
+						most = a
+					}
+				}
+				var out string
+				step := (to - from) / float64(len(amount))
+				for i, a := range amount {
+					out += fmt.Sprintf("%10.3f %6d %s\n",
+						from+step*float64(i), a,
+						strings.Repeat("#", int(20*a/most)))
+				}
+				res.SetText(out)
+			}
+			list.Add(&b)
+			list.Add(&res)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Color by metric", "Hide colors"}...)
+			list.Add(&rg)
+
+			var v vl.Button
+			v.SetText("Apply")
+			v.OnClick = func() {
+				m.QualityView(rg.GetPos() == 0, Metric(metrics.GetPos()))
+			}
+			list.Add(&v)
+
+			fr, frgt, initfr := InputFloat("From", "", 0)
+			list.Add(fr)
+			tw, togt, initto := InputFloat("To", "", 1)
+			list.Add(tw)
+
+			var s vl.Button
+			s.SetText("Select by threshold")
+			s.OnClick = func() {
+				from, ok := frgt()
+				if !ok {
+					return
+				}
+				to, ok := togt()
+				if !ok {
+					return
+				}
+				m.SelectByQuality(Metric(metrics.GetPos()), from, to)
+			}
+			list.Add(&s)
+			return &list, func() {
+				res.SetText("")
+				initin()
+				initfr()
+				initto()
+			}
+		}},
+	}
+	for i := range ops {
+		ops[i].Group = group
+	}
+	Operations = append(Operations, ops...)
+}
+
+type Measurementable interface {
+	DistanceNodes(n1, n2 uint) (distance float64, dim Dimension, ok bool)
+	DistanceLines(l1, l2 uint) (distance float64, dim Dimension, ok bool)
+	DistancePlates(p1, p2 uint) (distance float64, dim Dimension, ok bool)
+	AngleLines(l1, l2 uint) (angle float64, dim Dimension, ok bool)
+	TotalLength(elements []uint) (length float64)
+	TotalArea(elements []uint) (area float64)
+	Takeoff() (rows []Takeoff)
+	ExportTakeoff(filename string) error
+	AddDimension(d Dimension)
+	ClearDimensions()
+}
+
+func init() {
+	group := Measurement
+	// measure between 2 selected objects
+	between := func(
+		name string,
+		selector func(m Mesh) func(single bool) []uint,
+		measure func(m Mesh, a, b uint) (float64, Dimension, bool),
+	) func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+		return func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			s1, s1gt, init1 := Select("First "+name, Single, selector(m))
+			list.Add(s1)
+			s2, s2gt, init2 := Select("Second "+name, Single, selector(m))
+			list.Add(s2)
+
+			var dim vl.CheckBox
+			dim.SetText("Show dimension in 3D")
+			list.Add(&dim)
+
+			var res vl.Text
+			var b vl.Button
+			b.SetText("Measure")
+			b.OnClick = func() {
+				a, ok := isOne(s1gt)
+				if !ok {
+					return
+				}
+				c, ok := isOne(s2gt)
+				if !ok {
+					return
+				}
+				v, d, ok := measure(m, a, c)
+				if !ok {
+					res.SetText("Not valid " + name + "s")
+					return
+				}
+				res.SetText(fmt.Sprintf("Result: %.6f", v))
+				if dim.Checked {
+					m.AddDimension(d)
+				}
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				init1()
+				init2()
+				res.SetText("")
+			}
+		}
+	}
+	nodes := func(m Mesh) func(single bool) []uint {
+		return m.GetSelectNodes
+	}
+	elements := func(filter func(t ElType) bool) func(m Mesh) func(single bool) []uint {
+		return func(m Mesh) func(single bool) []uint {
+			return func(single bool) []uint {
+				return m.GetSelectElements(single, filter)
+			}
+		}
+	}
+	lines := elements(func(t ElType) bool { return t == Line2 })
+	plates := elements(func(t ElType) bool { return t == Triangle3 || t == Quadr4 })
+	ops := []Operation{{
+		Name: "Distance between nodes",
+		Part: between("node", nodes, func(m Mesh, a, b uint) (float64, Dimension, bool) {
+			return m.DistanceNodes(a, b)
+		}),
+	}, {
+		Name: "Distance between parallel lines",
+		Part: between("line", lines, func(m Mesh, a, b uint) (float64, Dimension, bool) {
+			return m.DistanceLines(a, b)
+		}),
+	}, {
+		Name: "Distance between parallel plates",
+		Part: between("plate", plates, func(m Mesh, a, b uint) (float64, Dimension, bool) {
+			return m.DistancePlates(a, b)
+		}),
+	}, {
+		Name: "Angle between lines",
+		Part: between("line", lines, func(m Mesh, a, b uint) (float64, Dimension, bool) {
+			return m.AngleLines(a, b)
+		}),
+	}, {
+		Name: "Total length and area",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			s, sgt, inits := Select("Select elements", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, nil)
+			})
+			list.Add(s)
+
+			var res vl.Text
+			var b vl.Button
+			b.SetText("Calculate")
+			b.OnClick = func() {
+				els := sgt()
+				res.SetText(fmt.Sprintf("Total length: %.6f\nTotal area: %.6f",
+					m.TotalLength(els), m.TotalArea(els)))
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				inits()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Mass properties",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var res vl.Text
+			var b vl.Button
+			b.SetText("Calculate")
+			b.OnClick = func() {
+				var out string
+				for _, t := range m.Takeoff() {
+					out += fmt.Sprintf("%s\n", t.Name)
+					out += fmt.Sprintf("  Length: %.5g\n", t.Length)
+					out += fmt.Sprintf("  Area: %.5g\n", t.Area)
+					out += fmt.Sprintf("  Paint area: %.5g\n", t.Paint)
+					out += fmt.Sprintf("  Weight: %.5g\n", t.Weight)
+					out += fmt.Sprintf("  Centroid: %.5g, %.5g, %.5g\n",
+						t.Centroid[0], t.Centroid[1], t.Centroid[2])
+				}
+				res.SetText(out)
+			}
+			list.Add(&b)
+			list.Add(&res)
+
+			var e vl.Button
+			e.SetText("Export CSV")
+			e.OnClick = func() {
+				name, err := zenity.SelectFileSave(
+					zenity.ConfirmOverwrite(),
+					zenity.Filename("takeoff.csv"),
+					zenity.FileFilters{
+						{Name: "csv files", Patterns: []string{"*.csv"}, CaseFold: false},
+					})
+				if err != nil {
+					// ignore error
+					return
+				}
+				if err = m.ExportTakeoff(name); err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+				}
+			}
+			list.Add(&e)
+			return &list, func() {
+				res.SetText("")
+			}
+		}}, {
+		Name: "Clear dimensions",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var b vl.Button
+			b.SetText("Clear")
+			b.OnClick = func() {
+				m.ClearDimensions()
+			}
+			return &b, func() {
+				m.ClearDimensions()
+			}
+		}},
+	}
+	for i := range ops {
+		ops[i].Group = group
+	}
+	Operations = append(Operations, ops...)
+}
+
+type Analysable interface {
+	LinearStatic() (report string, err error)
+	ExportStaticTable(filename string) error
+	GetDisplacements() (ds [][6]float64)
+	DeformedShape(show bool, scale float64)
+	Modal(modes uint, lumped bool) (report string, err error)
+	GetModeShape(mode uint) (ds [][6]float64)
+	ModeShape(show bool, mode uint, scale float64, animate bool)
+	Buckling(modes uint) (report string, err error)
+	GetBucklingShape(mode uint) (ds [][6]float64)
+	BucklingShape(show bool, mode uint, scale float64)
+	Imperfection(amplitude float64, factors []float64) error
+	GetField(f ResultField) (nodes, elements []float64)
+	Contour(show bool, field ResultField, deformed bool, scale float64)
+}
+
+func init() {
+	group := Analysis
+	ops := []Operation{{
+		Name: "Linear static analysis",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var res vl.Text
+
+			var b vl.Button
+			b.SetText("Run")
+			b.OnClick = func() {
+				report, err := m.LinearStatic()
+				if err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+					return
+				}
+				res.SetText(report)
+			}
+			list.Add(&b)
+			list.Add(&res)
+
+			var e vl.Button
+			e.SetText("Export table")
+			e.OnClick = func() {
+				name, err := zenity.SelectFileSave(
+					zenity.ConfirmOverwrite(),
+					zenity.Filename("results.csv"),
+					zenity.FileFilters{
+						{Name: "csv files", Patterns: []string{"*.csv"}, CaseFold: false},
+					})
+				if err != nil {
+					// ignore error
+					return
+				}
+				if err = m.ExportStaticTable(name); err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+				}
+			}
+			list.Add(&e)
+			return &list, func() {
+				res.SetText("")
+			}
+		}}, {
+		Name: "Deformed shape",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			s, sgt, sinit := InputFloat("Scale", "(zero is automatic)", 0)
+			list.Add(s)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Show deformed shape", "Hide deformed shape"}...)
+			list.Add(&rg)
+
+			var b vl.Button
+			b.SetText("Apply")
+			b.OnClick = func() {
+				scale, ok := sgt()
+				if !ok {
+					return
+				}
+				m.DeformedShape(rg.GetPos() == 0, scale)
+			}
+			list.Add(&b)
+			return &list, func() {
+				sinit()
+			}
+		}}, {
+		Name: "Modal analysis",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			n, ngt, ninit := InputUnsigned("Amount of modes", "", 6)
+			list.Add(n)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Consistent mass", "Lumped mass"}...)
+			list.Add(&rg)
+
+			var res vl.Text
+
+			var b vl.Button
+			b.SetText("Run")
+			b.OnClick = func() {
+				modes, ok := ngt()
+				if !ok {
+					return
+				}
+				report, err := m.Modal(modes, rg.GetPos() == 1)
+				if err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+					return
+				}
+				res.SetText(report)
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				ninit()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Mode shape",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
 
-type Measurementable interface {
-	// Distance between 2 nodes
-	// Distance between 2 parallel beam
-	// Distance between 2 parallel plates
-}
+			n, ngt, ninit := InputUnsigned("Mode", "", 1)
+			list.Add(n)
 
-// func init() {
-// 	ops := []Operation{{}}
-// 	for i := range ops {
-// 		ops[i].Group = Measurement
-// 	}
-// 	Operations = append(Operations, ops...)
-// }
+			s, sgt, sinit := InputFloat("Scale", "(zero is automatic)", 0)
+			list.Add(s)
+
+			var animate vl.CheckBox
+			animate.SetText("Animation")
+			animate.Checked = true
+			list.Add(&animate)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Show mode shape", "Hide mode shape"}...)
+			list.Add(&rg)
+
+			var b vl.Button
+			b.SetText("Apply")
+			b.OnClick = func() {
+				mode, ok := ngt()
+				if !ok || mode == 0 {
+					return
+				}
+				scale, ok := sgt()
+				if !ok {
+					return
+				}
+				m.ModeShape(rg.GetPos() == 0, mode-1, scale, animate.Checked)
+			}
+			list.Add(&b)
+			return &list, func() {
+				ninit()
+				sinit()
+			}
+		}}, {
+		Name: "Linear buckling analysis",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			n, ngt, ninit := InputUnsigned("Amount of modes", "", 4)
+			list.Add(n)
+
+			var res vl.Text
+
+			var b vl.Button
+			b.SetText("Run")
+			b.OnClick = func() {
+				modes, ok := ngt()
+				if !ok {
+					return
+				}
+				report, err := m.Buckling(modes)
+				if err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+					return
+				}
+				res.SetText(report)
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				ninit()
+				res.SetText("")
+			}
+		}}, {
+		Name: "Buckling shape",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			n, ngt, ninit := InputUnsigned("Mode", "", 1)
+			list.Add(n)
+
+			s, sgt, sinit := InputFloat("Scale", "(zero is automatic)", 0)
+			list.Add(s)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Show buckling shape", "Hide buckling shape"}...)
+			list.Add(&rg)
+
+			var b vl.Button
+			b.SetText("Apply")
+			b.OnClick = func() {
+				mode, ok := ngt()
+				if !ok || mode == 0 {
+					return
+				}
+				scale, ok := sgt()
+				if !ok {
+					return
+				}
+				m.BucklingShape(rg.GetPos() == 0, mode-1, scale)
+			}
+			list.Add(&b)
+			return &list, func() {
+				ninit()
+				sinit()
+			}
+		}}, {
+		Name: "Buckling imperfection",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			a, agt, ainit := InputFloat("Amplitude", "", 0.001)
+			list.Add(a)
+
+			var text vl.Text
+			text.SetText("Factors of buckling shapes, for example: 0.75 0.25")
+			list.Add(&text)
+
+			var fs vl.InputBox
+			fs.SetText("1")
+			list.Add(&fs)
+
+			var res vl.Text
+
+			var b vl.Button
+			b.SetText("Apply")
+			b.OnClick = func() {
+				amplitude, ok := agt()
+				if !ok {
+					return
+				}
+				var factors []float64
+				for _, s := range strings.Fields(fs.GetText()) {
+					f, err := strconv.ParseFloat(s, 64)
+					if err != nil {
+						res.SetText(fmt.Sprintf("%v", err))
+						return
+					}
+					factors = append(factors, f)
+				}
+				if err := m.Imperfection(amplitude, factors); err != nil {
+					res.SetText(fmt.Sprintf("%v", err))
+					return
+				}
+				res.SetText("Coordinates are changed")
+			}
+			list.Add(&b)
+			list.Add(&res)
+			return &list, func() {
+				ainit()
+				fs.SetText("1")
+				res.SetText("")
+			}
+		}}, {
+		Name: "Contour view",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var fields vl.ComboBox
+			for f := DisplacementField; f < endResultField; f++ {
+				fields.Add(f.String())
+			}
+			list.Add(&fields)
+
+			var deformed vl.CheckBox
+			deformed.SetText("Deformed shape")
+			list.Add(&deformed)
+
+			s, sgt, sinit := InputFloat("Scale", "(zero is automatic)", 0)
+			list.Add(s)
+
+			var rg vl.RadioGroup
+			rg.AddText([]string{"Show contour", "Hide contour"}...)
+			list.Add(&rg)
+
+			var b vl.Button
+			b.SetText("Apply")
+			b.OnClick = func() {
+				scale, ok := sgt()
+				if !ok {
+					return
+				}
+				m.Contour(rg.GetPos() == 0, ResultField(fields.GetPos()), deformed.Checked, scale)
+			}
+			list.Add(&b)
+			return &list, func() {
+				sinit()
+			}
+		}},
+	}
+	for i := range ops {
+		ops[i].Group = group
+	}
+	Operations = append(Operations, ops...)
+}
 
 type Pluginable interface {
 	DemoSpiral(levels uint)
@@ -2433,10 +3603,17 @@ type Pluginable interface {
 	// Split plates by lines
 	// Split lines by plates
 	// Convert triangles to rectangles
+	ConvertTri3ToQuadr4(tris []uint)
 	// Convert rectangles to triangles
+	SplitQuadr4To2Tri3(q4s []uint)
+	SplitQuadr4To4Tri3(q4s []uint)
 	// Plate bending
 	// Twist
 	// Extrude
+	Extrude(elements []uint, vector [3]float64, parts uint) (created []uint)
+	ExtrudeByPath(elements, path []uint, parts uint) (created []uint)
+	// Revolve
+	Revolve(lines []uint, axis [2]uint, angle float64, segments uint) (created []uint)
 	// Hole circle, square, rectangle on direction
 	// Cutoff
 	// Bend plates
@@ -2475,6 +3652,191 @@ func init() {
 			return &list, func() {
 				initr()
 			}
+		}}, {
+		Name: "Extrude",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			var inits []func()
+			es, esgt, inite := Select("Select lines and quadr4", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2 || t == Quadr4
+				})
+			})
+			list.Add(es)
+			inits = append(inits, inite)
+
+			var gts []func() (float64, bool)
+			var vlist vl.List
+			for _, name := range []string{"dX", "dY", "dZ"} {
+				w, gt, initw := InputFloat(name+":", "meter", 0)
+				vlist.Add(w)
+				inits = append(inits, initw)
+				gts = append(gts, gt)
+			}
+			var vch vl.CollapsingHeader
+			vch.BorderIfClosed(false)
+			vch.SetText("By vector:")
+			vch.SetRoot(&vlist)
+
+			ps, psgt, initp := Select("Select path lines", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			})
+			inits = append(inits, initp)
+			var pch vl.CollapsingHeader
+			pch.BorderIfClosed(false)
+			pch.SetText("By path:")
+			pch.SetRoot(ps)
+
+			list.Add(vl.TextStatic("Choose direction:"))
+			var param vl.RadioGroup
+			param.Add(&vch)
+			param.Add(&pch)
+			list.Add(&param)
+
+			parts, pgt, initparts := InputUnsigned("Amount parts", "", 1)
+			list.Add(parts)
+			inits = append(inits, initparts)
+
+			var b vl.Button
+			b.SetText("Extrude")
+			b.OnClick = func() {
+				n, ok := pgt()
+				if !ok {
+					return
+				}
+				if param.GetPos() == 1 {
+					m.ExtrudeByPath(esgt(), psgt(), n)
+					return
+				}
+				var v [3]float64
+				for i := range v {
+					if v[i], ok = gts[i](); !ok {
+						return
+					}
+				}
+				m.Extrude(esgt(), v, n)
+			}
+			list.Add(&b)
+			return &list, func() {
+				for i := range inits {
+					inits[i]()
+				}
+			}
+		}}, {
+		Name: "Revolve",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+
+			ls, lsgt, initl := Select("Select lines", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Line2
+				})
+			})
+			list.Add(ls)
+
+			n0, n0gt, init0 := Select("Select axis node 1", Single, m.GetSelectNodes)
+			list.Add(n0)
+			n1, n1gt, init1 := Select("Select axis node 2", Single, m.GetSelectNodes)
+			list.Add(n1)
+
+			angle, agt, inita := InputFloat("Angle", "degree", 360)
+			list.Add(angle)
+
+			segments, sgt, inits := InputUnsigned("Amount segments", "", 16)
+			list.Add(segments)
+
+			var b vl.Button
+			b.SetText("Revolve")
+			b.OnClick = func() {
+				a0, ok := isOne(n0gt)
+				if !ok {
+					return
+				}
+				a1, ok := isOne(n1gt)
+				if !ok {
+					return
+				}
+				a, ok := agt()
+				if !ok {
+					return
+				}
+				n, ok := sgt()
+				if !ok {
+					return
+				}
+				m.Revolve(lsgt(), [2]uint{a0, a1}, a, n)
+			}
+			list.Add(&b)
+			return &list, func() {
+				initl()
+				init0()
+				init1()
+				inita()
+				inits()
+			}
+		}}, {
+		Name: "Convert Triangle3 to Quadr4",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			ns, nsgt, initn := Select("Select triangles3", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Triangle3
+				})
+			})
+			list.Add(ns)
+
+			var b vl.Button
+			b.SetText("Convert")
+			b.OnClick = func() {
+				m.ConvertTri3ToQuadr4(nsgt())
+			}
+			list.Add(&b)
+			return &list, func() {
+				initn()
+			}
+		}}, {
+		Name: "Convert Quadr4 to 2 Triangle3",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			ns, nsgt, initn := Select("Select quadr4", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Quadr4
+				})
+			})
+			list.Add(ns)
+
+			var b vl.Button
+			b.SetText("Convert")
+			b.OnClick = func() {
+				m.SplitQuadr4To2Tri3(nsgt())
+			}
+			list.Add(&b)
+			return &list, func() {
+				initn()
+			}
+		}}, {
+		Name: "Convert Quadr4 to 4 Triangle3",
+		Part: func(m Mesh, actions *chan ds.Action, closedApp *bool) (w vl.Widget, f func()) {
+			var list vl.List
+			ns, nsgt, initn := Select("Select quadr4", Many, func(single bool) []uint {
+				return m.GetSelectElements(single, func(t ElType) bool {
+					return t == Quadr4
+				})
+			})
+			list.Add(ns)
+
+			var b vl.Button
+			b.SetText("Convert")
+			b.OnClick = func() {
+				m.SplitQuadr4To4Tri3(nsgt())
+			}
+			list.Add(&b)
+			return &list, func() {
+				initn()
+			}
 		}},
 	}
 	for i := range ops {
@@ -2496,6 +3858,7 @@ type Mesh interface {
 	Checkable
 	Pluginable
 	Measurementable
+	Analysable
 
 	groups.Mesh
 }
@@ -34,6 +34,8 @@ func (u *Undo) sync(isUndo bool) (pre, post func()) {
 			if !isUndo {
 				u.addToUndo() // store model in undo list
 			}
+			// results is not valid for changed model
+			u.model.results = results{}
 		}, func() {
 			u.changed = true
 			// u.op.UpdateModel() // update camera view
@@ -175,6 +177,11 @@ func (u *Undo) ViewAll() {
 	u.op.ViewAll()
 }
 
+func (u *Undo) ZoomSelected() {
+	logger.Print("ZoomSelected")
+	u.op.ZoomSelected()
+}
+
 func (u *Undo) AddNode(X, Y, Z float64) (id uint) {
 	logger.Print("AddNode")
 	// sync
@@ -215,6 +222,16 @@ func (u *Undo) AddQuadr4ByNodeNumber(n1, n2, n3, n4 uint) (id uint, ok bool) {
 	return u.model.AddQuadr4ByNodeNumber(n1, n2, n3, n4)
 }
 
+func (u *Undo) AddHexa8ByNodeNumber(ns [8]uint) (id uint, ok bool) {
+	logger.Print("AddHexa8ByNodeNumber")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.AddHexa8ByNodeNumber(ns)
+}
+
 func (u *Undo) GetCoordByID(id uint) (_ gog.Point3d, ok bool) {
 	logger.Print("GetCoordByID")
 	return u.model.GetCoordByID(id)
@@ -403,6 +420,26 @@ func (u *Undo) SplitTri3To3Tri3(tris []uint) {
 	u.model.SplitTri3To3Tri3(tris)
 }
 
+func (u *Undo) SplitTri3To4Tri3(tris []uint) {
+	logger.Print("SplitTri3To4Tri3")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.SplitTri3To4Tri3(tris)
+}
+
+func (u *Undo) SplitQuadr4To4Quadr4(q4s []uint) {
+	logger.Print("SplitQuadr4To4Quadr4")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.SplitQuadr4To4Quadr4(q4s)
+}
+
 func (u *Undo) MergeNodes(minDistance float64) {
 	logger.Print("MergeNodes")
 	// sync
@@ -423,6 +460,46 @@ func (u *Undo) MergeLines(lines []uint) {
 	u.model.MergeLines(lines)
 }
 
+func (u *Undo) Triangulation(nodes, lines []uint) (triangles []uint) {
+	logger.Print("Triangulation")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.Triangulation(nodes, lines)
+}
+
+func (u *Undo) MeshArea(lines []uint, size float64, quadr bool) (elements []uint) {
+	logger.Print("MeshArea")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.MeshArea(lines, size, quadr)
+}
+
+func (u *Undo) SmoothMesh(plates []uint, iterations uint, guarded bool) (before, after float64) {
+	logger.Print("SmoothMesh")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.SmoothMesh(plates, iterations, guarded)
+}
+
+func (u *Undo) SplitByPlane(plane [3]gog.Point3d, elements []uint, section bool) (lines []uint) {
+	logger.Print("SplitByPlane")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.SplitByPlane(plane, elements, section)
+}
+
 func (u *Undo) ScaleOrtho(basePoint gog.Point3d,
 	scale [3]float64,
 	nodes, elements []uint,
@@ -484,6 +561,89 @@ func (u *Undo) Mirror(nodes, elements []uint,
 	u.model.Mirror(nodes, elements, basePoint, copy, addLines, addTri)
 }
 
+func (u *Undo) CopyByPath(nodes, elements []uint,
+	path []uint,
+	withRotation bool,
+	copy, addLines, addTri bool) {
+	logger.Print("CopyByPath")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.CopyByPath(nodes, elements, path, withRotation, copy, addLines, addTri)
+}
+
+func (u *Undo) Loft(from, to []uint, sections uint) (created []uint) {
+	logger.Print("Loft")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.Loft(from, to, sections)
+}
+
+func (u *Undo) SplitQuadr4To2Tri3(q4s []uint) {
+	logger.Print("SplitQuadr4To2Tri3")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.SplitQuadr4To2Tri3(q4s)
+}
+
+func (u *Undo) SplitQuadr4To4Tri3(q4s []uint) {
+	logger.Print("SplitQuadr4To4Tri3")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.SplitQuadr4To4Tri3(q4s)
+}
+
+func (u *Undo) ConvertTri3ToQuadr4(tris []uint) {
+	logger.Print("ConvertTri3ToQuadr4")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.ConvertTri3ToQuadr4(tris)
+}
+
+func (u *Undo) Extrude(elements []uint, vector [3]float64, parts uint) (created []uint) {
+	logger.Print("Extrude")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.Extrude(elements, vector, parts)
+}
+
+func (u *Undo) ExtrudeByPath(elements, path []uint, parts uint) (created []uint) {
+	logger.Print("ExtrudeByPath")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.ExtrudeByPath(elements, path, parts)
+}
+
+func (u *Undo) Revolve(lines []uint, axis [2]uint, angle float64, segments uint) (created []uint) {
+	logger.Print("Revolve")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	return u.model.Revolve(lines, axis, angle, segments)
+}
+
 func (u *Undo) DemoSpiral(n uint) {
 	logger.Print("DemoSpiral")
 	// sync
@@ -554,6 +714,121 @@ func (u *Undo) Check() error {
 	return u.model.Check()
 }
 
+func (u *Undo) CheckAll() (findings []Finding) {
+	logger.Print("CheckAll")
+	return u.model.CheckAll()
+}
+
+func (u *Undo) Quality(metric Metric) (values []float64) {
+	// too many : logger.Print("Quality")
+	return u.model.Quality(metric)
+}
+
+func (u *Undo) QualityHistogram(metric Metric, intervals uint) (from, to float64, amount []uint) {
+	logger.Print("QualityHistogram")
+	return u.model.QualityHistogram(metric, intervals)
+}
+
+func (u *Undo) SelectByQuality(metric Metric, from, to float64) {
+	logger.Print("SelectByQuality")
+	u.model.SelectByQuality(metric, from, to)
+}
+
+func (u *Undo) QualityView(show bool, metric Metric) {
+	logger.Print("QualityView")
+	u.op.QualityView(show, metric)
+}
+
+func (u *Undo) CheckSingleStructure() (parts []Structure) {
+	logger.Print("CheckSingleStructure")
+	return u.model.CheckSingleStructure()
+}
+
+func (u *Undo) CheckDuplicateElements() (duplicates [][]uint) {
+	logger.Print("CheckDuplicateElements")
+	return u.model.CheckDuplicateElements()
+}
+
+func (u *Undo) RemoveDuplicateElements() {
+	logger.Print("RemoveDuplicateElements")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.RemoveDuplicateElements()
+}
+
+func (u *Undo) CheckLinesOverlapping() (pairs [][2]uint) {
+	logger.Print("CheckLinesOverlapping")
+	return u.model.CheckLinesOverlapping()
+}
+
+func (u *Undo) FixLinesOverlapping() {
+	logger.Print("FixLinesOverlapping")
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.FixLinesOverlapping()
+}
+
+func (u *Undo) CheckPlates(warping float64) (defects [endPlateDefect][]uint) {
+	logger.Print("CheckPlates")
+	return u.model.CheckPlates(warping)
+}
+
+func (u *Undo) DistanceNodes(n1, n2 uint) (distance float64, dim Dimension, ok bool) {
+	logger.Print("DistanceNodes")
+	return u.model.DistanceNodes(n1, n2)
+}
+
+func (u *Undo) DistanceLines(l1, l2 uint) (distance float64, dim Dimension, ok bool) {
+	logger.Print("DistanceLines")
+	return u.model.DistanceLines(l1, l2)
+}
+
+func (u *Undo) DistancePlates(p1, p2 uint) (distance float64, dim Dimension, ok bool) {
+	logger.Print("DistancePlates")
+	return u.model.DistancePlates(p1, p2)
+}
+
+func (u *Undo) AngleLines(l1, l2 uint) (angle float64, dim Dimension, ok bool) {
+	logger.Print("AngleLines")
+	return u.model.AngleLines(l1, l2)
+}
+
+func (u *Undo) TotalLength(elements []uint) (length float64) {
+	logger.Print("TotalLength")
+	return u.model.TotalLength(elements)
+}
+
+func (u *Undo) TotalArea(elements []uint) (area float64) {
+	logger.Print("TotalArea")
+	return u.model.TotalArea(elements)
+}
+
+func (u *Undo) Takeoff() (rows []Takeoff) {
+	logger.Print("Takeoff")
+	return u.model.Takeoff()
+}
+
+func (u *Undo) ExportTakeoff(filename string) error {
+	logger.Print("ExportTakeoff")
+	return u.model.ExportTakeoff(filename)
+}
+
+func (u *Undo) AddDimension(d Dimension) {
+	logger.Print("AddDimension")
+	u.op.AddDimension(d)
+}
+
+func (u *Undo) ClearDimensions() {
+	logger.Print("ClearDimensions")
+	u.op.ClearDimensions()
+}
+
 func (u *Undo) GetRootGroup() groups.Group {
 	// action
 	return u.model.GetRootGroup()
@@ -568,3 +843,76 @@ func (u *Undo) Update(nodes, elements *uint) {
 	// action
 	u.model.Update(nodes, elements)
 }
+
+func (u *Undo) LinearStatic() (report string, err error) {
+	logger.Print("LinearStatic")
+	return u.model.LinearStatic()
+}
+
+func (u *Undo) ExportStaticTable(filename string) error {
+	logger.Print("ExportStaticTable")
+	return u.model.ExportStaticTable(filename)
+}
+
+func (u *Undo) GetDisplacements() (ds [][6]float64) {
+	// too many : logger.Print("GetDisplacements")
+	return u.model.GetDisplacements()
+}
+
+func (u *Undo) DeformedShape(show bool, scale float64) {
+	logger.Print("DeformedShape")
+	u.op.DeformedShape(show, scale)
+}
+
+func (u *Undo) Modal(modes uint, lumped bool) (report string, err error) {
+	logger.Print("Modal")
+	return u.model.Modal(modes, lumped)
+}
+
+func (u *Undo) GetModeShape(mode uint) (ds [][6]float64) {
+	// too many : logger.Print("GetModeShape")
+	return u.model.GetModeShape(mode)
+}
+
+func (u *Undo) ModeShape(show bool, mode uint, scale float64, animate bool) {
+	logger.Print("ModeShape")
+	u.op.ModeShape(show, mode, scale, animate)
+}
+
+func (u *Undo) Buckling(modes uint) (report string, err error) {
+	logger.Print("Buckling")
+	return u.model.Buckling(modes)
+}
+
+func (u *Undo) GetBucklingShape(mode uint) (ds [][6]float64) {
+	// too many : logger.Print("GetBucklingShape")
+	return u.model.GetBucklingShape(mode)
+}
+
+func (u *Undo) BucklingShape(show bool, mode uint, scale float64) {
+	logger.Print("BucklingShape")
+	u.op.BucklingShape(show, mode, scale)
+}
+
+func (u *Undo) GetField(f ResultField) (nodes, elements []float64) {
+	// too many : logger.Print("GetField")
+	return u.model.GetField(f)
+}
+
+func (u *Undo) Contour(show bool, field ResultField, deformed bool, scale float64) {
+	logger.Print("Contour")
+	u.op.Contour(show, field, deformed, scale)
+}
+
+func (u *Undo) Imperfection(amplitude float64, factors []float64) error {
+	logger.Print("Imperfection")
+	// buckling shapes is needed for imperfection
+	res := u.model.results
+	// sync
+	pre, post := u.sync(false)
+	pre()
+	defer post()
+	// action
+	u.model.results = res
+	return u.model.Imperfection(amplitude, factors)
+}
@@ -2,10 +2,14 @@ package ms
 
 import (
 	"log"
+	"math"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/Konstantin8105/gog"
+	"github.com/Konstantin8105/ms/groups"
 )
 
 var logger *log.Logger
@@ -90,3 +94,72 @@ func uniqUint(ids []uint) (res []uint) {
 	}
 	return
 }
+
+// walkGroups run function for each group of tree
+func walkGroups(gr groups.Group, f func(gr groups.Group)) {
+	if gr == nil {
+		return
+	}
+	f(gr)
+	switch n := gr.(type) {
+	case *groups.Meta:
+		for i := range n.Groups {
+			walkGroups(n.Groups[i], f)
+		}
+	}
+}
+
+// vector return vector from point a to point b
+func vector(a, b gog.Point3d) (v [3]float64) {
+	for i := range v {
+		v[i] = b[i] - a[i]
+	}
+	return
+}
+
+// cross return cross product of vectors
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// dot return dot product of vectors
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// norm return length of vector
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+// rotate return point rotated around axis through origin on angle in
+// radians by Rodrigues formula. Axis is unit vector.
+func rotate(p, origin gog.Point3d, axis [3]float64, angle float64) (r gog.Point3d) {
+	v := vector(origin, p)
+	c := cross(axis, v)
+	d := dot(axis, v)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	for i := range r {
+		r[i] = origin[i] + v[i]*cos + c[i]*sin + axis[i]*d*(1-cos)
+	}
+	return
+}
+
+// anyNormal return unit vector perpendicular to vector
+func anyNormal(v [3]float64) (n [3]float64) {
+	axe := [3]float64{1, 0, 0}
+	if math.Abs(v[1]) < math.Abs(v[0]) {
+		axe = [3]float64{0, 1, 0}
+	}
+	n = cross(v, axe)
+	if l := norm(n); 0 < l {
+		for i := range n {
+			n[i] /= l
+		}
+	}
+	return
+}